@@ -0,0 +1,427 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/amp-labs/cli/files"
+	"github.com/amp-labs/cli/internal/metadata"
+	"github.com/amp-labs/cli/internal/scaffold"
+	"github.com/amp-labs/cli/logger"
+	"github.com/amp-labs/cli/openapi"
+	"github.com/spf13/cobra"
+)
+
+var (
+	errNoManifest          = errors.New("no manifest to add to, run 'amp init' first")
+	errIntegrationNotFound = errors.New("integration not found in the manifest")
+	errChooseIntegration   = errors.New("the manifest has several integrations, please name one")
+	errAlreadyDeclared     = errors.New("already in the manifest")
+
+	addManifestFile  string //nolint:gochecknoglobals
+	addRequiredField bool   //nolint:gochecknoglobals
+
+	addCmd = &cobra.Command{ //nolint:gochecknoglobals
+		Use:   "add",
+		Short: "Add integrations, objects and fields to amp.yaml",
+		Long: `Add integrations, objects and fields to an existing amp.yaml.
+
+The subcommands ask the same questions as 'amp init', and insert the answers into the
+manifest without touching the rest of it, so comments and formatting are kept.
+The integration can be left out when the manifest has only one. Fields are picked
+the same way as with 'amp init'.
+
+Examples:
+  amp add integration
+  amp add read-object my-integration
+  amp add write-object
+  amp add subscribe-object my-integration
+  amp add field my-integration Account --required`,
+		Hidden: true,
+	}
+
+	addIntegrationCmd = &cobra.Command{ //nolint:gochecknoglobals
+		Use:   "integration",
+		Short: "Add an integration",
+		Args:  cobra.NoArgs,
+		RunE:  runAddIntegration,
+	}
+
+	addReadObjectCmd = &cobra.Command{ //nolint:gochecknoglobals
+		Use:   "read-object [integration]",
+		Short: "Add an object to read",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  runAddReadObject,
+	}
+
+	addWriteObjectCmd = &cobra.Command{ //nolint:gochecknoglobals
+		Use:   "write-object [integration]",
+		Short: "Add an object to write",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  runAddWriteObject,
+	}
+
+	addSubscribeObjectCmd = &cobra.Command{ //nolint:gochecknoglobals
+		Use:   "subscribe-object [integration]",
+		Short: "Add an object to subscribe to",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  runAddSubscribeObject,
+	}
+
+	addFieldCmd = &cobra.Command{ //nolint:gochecknoglobals
+		Use:   "field [integration] <object>",
+		Short: "Add a field to a read object",
+		Args:  cobra.RangeArgs(1, 2), //nolint:mnd
+		RunE:  runAddField,
+	}
+)
+
+func init() {
+	addCmd.PersistentFlags().StringVar(&addManifestFile, "manifest", "amp.yaml", "The manifest to add to")
+	addFieldCmd.Flags().BoolVar(&addRequiredField, "required", false,
+		"Add a required field instead of an optional one")
+
+	for _, cmd := range []*cobra.Command{addIntegrationCmd, addReadObjectCmd, addSubscribeObjectCmd, addFieldCmd} {
+		addFieldSourceFlags(cmd)
+	}
+
+	addCmd.AddCommand(addIntegrationCmd, addReadObjectCmd, addWriteObjectCmd, addSubscribeObjectCmd, addFieldCmd)
+	rootCmd.AddCommand(addCmd)
+}
+
+func runAddIntegration(cmd *cobra.Command, _ []string) error {
+	doc, manifest, err := loadManifestDocument()
+	if err != nil {
+		return err
+	}
+
+	answers, provider, err := promptAnswers(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	if _, err := findIntegration(manifest, []string{answers.Name}); err == nil {
+		return fmt.Errorf("integration %s is %w", answers.Name, errAlreadyDeclared)
+	}
+
+	integ, err := answers.Integration(provider)
+	if err != nil {
+		return err
+	}
+
+	err = doc.AddIntegration(integ)
+	if err != nil {
+		return err
+	}
+
+	return saveManifestDocument(doc, "integration "+integ.Name)
+}
+
+func runAddReadObject(cmd *cobra.Command, args []string) error {
+	doc, integ, provider, err := prepareAdd(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	if !provider.Support.Read {
+		return fmt.Errorf("read is %w %s", scaffold.ErrUnsupported, provider.DisplayName)
+	}
+
+	answers := &scaffold.Answers{Name: integ.Name, Provider: integ.Provider, Module: integ.Module}
+
+	err = addReadObject(cmd.Context(), answers, provider)
+	if err != nil {
+		return err
+	}
+
+	built, err := answers.Integration(provider)
+	if err != nil {
+		return err
+	}
+
+	obj := (*built.Read.Objects)[0]
+	if readObjectNames(integ)[obj.ObjectName] {
+		return fmt.Errorf("read object %s is %w", obj.ObjectName, errAlreadyDeclared)
+	}
+
+	err = doc.AddReadObject(integ.Name, obj)
+	if err != nil {
+		return err
+	}
+
+	return saveManifestDocument(doc, "read object "+obj.ObjectName)
+}
+
+func runAddWriteObject(cmd *cobra.Command, args []string) error {
+	doc, integ, provider, err := prepareAdd(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	if !provider.Support.Write {
+		return fmt.Errorf("write is %w %s", scaffold.ErrUnsupported, provider.DisplayName)
+	}
+
+	answers := &scaffold.Answers{Name: integ.Name, Provider: integ.Provider, Module: integ.Module}
+
+	err = addWriteObject(answers, provider)
+	if err != nil {
+		return err
+	}
+
+	objName := answers.Write[0].ObjectName
+
+	if integ.Write != nil && integ.Write.Objects != nil {
+		for _, obj := range *integ.Write.Objects {
+			if obj.ObjectName == objName {
+				return fmt.Errorf("write object %s is %w", objName, errAlreadyDeclared)
+			}
+		}
+	}
+
+	err = doc.AddWriteObject(integ.Name, openapi.IntegrationWriteObject{ObjectName: objName})
+	if err != nil {
+		return err
+	}
+
+	return saveManifestDocument(doc, "write object "+objName)
+}
+
+func runAddSubscribeObject(cmd *cobra.Command, args []string) error {
+	doc, integ, provider, err := prepareAdd(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	if !provider.Support.Subscribe {
+		return fmt.Errorf("subscribe is %w %s", scaffold.ErrUnsupported, provider.DisplayName)
+	}
+
+	// The read objects let the new object inherit their fields and mapping
+	answers := &scaffold.Answers{Name: integ.Name, Provider: integ.Provider, Module: integ.Module}
+	for objName := range readObjectNames(integ) {
+		answers.Read = append(answers.Read, scaffold.ReadObject{ObjectName: objName})
+	}
+
+	err = addSubscribeObject(cmd.Context(), answers, provider)
+	if err != nil {
+		return err
+	}
+
+	built, err := answers.Integration(provider)
+	if err != nil {
+		return err
+	}
+
+	obj := (*built.Subscribe.Objects)[0]
+
+	if integ.Subscribe != nil && integ.Subscribe.Objects != nil {
+		for _, existing := range *integ.Subscribe.Objects {
+			if existing.ObjectName == obj.ObjectName {
+				return fmt.Errorf("subscribe object %s is %w", obj.ObjectName, errAlreadyDeclared)
+			}
+		}
+	}
+
+	err = doc.AddSubscribeObject(integ.Name, obj)
+	if err != nil {
+		return err
+	}
+
+	return saveManifestDocument(doc, "subscribe object "+obj.ObjectName)
+}
+
+func runAddField(cmd *cobra.Command, args []string) error {
+	objName := args[len(args)-1]
+
+	// Fields don't depend on the provider's support, so there is no need to look it up
+	doc, integ, err := loadIntegration(args[:len(args)-1])
+	if err != nil {
+		return err
+	}
+
+	err = useFieldSource(cmd.Context(), integ.Provider)
+	if err != nil {
+		return err
+	}
+
+	if !readObjectNames(integ)[objName] {
+		return fmt.Errorf("%w: read object %s of integration %s", scaffold.ErrNotFound, objName, integ.Name)
+	}
+
+	field, err := getIntegrationField(objectFields(cmd.Context(), objName))
+	if err != nil {
+		return err
+	}
+
+	if declaresField(integ, objName, field.FieldName) {
+		return fmt.Errorf("field %s of %s is %w", field.FieldName, objName, errAlreadyDeclared)
+	}
+
+	// Fields typed in may be missing from the metadata on purpose, e.g. if they were just
+	// created, so an unknown one is only worth a warning. Field mappings name no field.
+	if field.FieldName != "" && fieldSource != nil {
+		unknown, err := metadata.CheckField(cmd.Context(), fieldSource, objName, field.FieldName)
+		if err != nil {
+			return err
+		}
+
+		if unknown != nil {
+			logger.Infof("⚠️  %s (adding it anyway)", unknown)
+		}
+	}
+
+	built, err := field.IntegrationField()
+	if err != nil {
+		return err
+	}
+
+	err = doc.AddField(integ.Name, objName, addRequiredField, *built)
+	if err != nil {
+		return err
+	}
+
+	return saveManifestDocument(doc, "field "+field.FieldName+" of "+objName)
+}
+
+// prepareAdd loads the manifest, and finds the integration to add to along with its provider,
+// as seen through the integration's module.
+func prepareAdd(cmd *cobra.Command, args []string) (
+	*scaffold.Document, *openapi.Integration, *openapi.ProviderInfo, error,
+) {
+	doc, integ, err := loadIntegration(args)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	provider, err := lookupProvider(cmd.Context(), integ.Provider)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	err = useFieldSource(cmd.Context(), integ.Provider)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// What can be added depends on the module the integration uses
+	module, err := scaffold.ModuleProvider(provider, integ.Module)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return doc, integ, module, nil
+}
+
+// loadIntegration loads the manifest, and finds the integration named by the arguments.
+func loadIntegration(args []string) (*scaffold.Document, *openapi.Integration, error) {
+	doc, manifest, err := loadManifestDocument()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	integ, err := findIntegration(manifest, args)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return doc, integ, nil
+}
+
+// loadManifestDocument reads the manifest given by --manifest, both to edit it and to look
+// up what it already declares.
+func loadManifestDocument() (*scaffold.Document, *openapi.Manifest, error) {
+	data, err := os.ReadFile(addManifestFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil, fmt.Errorf("%w: %s is missing", errNoManifest, addManifestFile)
+		}
+
+		return nil, nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	manifest, err := files.ParseManifest(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	doc, err := scaffold.ParseDocument(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return doc, manifest, nil
+}
+
+func saveManifestDocument(doc *scaffold.Document, added string) error {
+	err := os.WriteFile(addManifestFile, doc.Bytes(), YamlFileMode) //nolint:gosec
+	if err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	fmt.Fprint(os.Stdout, "Added "+added+" to "+addManifestFile+"\n")
+
+	return nil
+}
+
+// findIntegration returns the integration named by the arguments, or the only one of the
+// manifest if they are empty.
+func findIntegration(manifest *openapi.Manifest, args []string) (*openapi.Integration, error) {
+	if len(args) == 0 {
+		if len(manifest.Integrations) != 1 {
+			names := make([]string, 0, len(manifest.Integrations))
+			for _, integ := range manifest.Integrations {
+				names = append(names, integ.Name)
+			}
+
+			return nil, fmt.Errorf("%w (%s)", errChooseIntegration, strings.Join(names, ", "))
+		}
+
+		return &manifest.Integrations[0], nil
+	}
+
+	for idx := range manifest.Integrations {
+		if manifest.Integrations[idx].Name == args[0] {
+			return &manifest.Integrations[idx], nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s", errIntegrationNotFound, args[0])
+}
+
+func readObjectNames(integ *openapi.Integration) map[string]bool {
+	names := map[string]bool{}
+
+	if integ.Read != nil && integ.Read.Objects != nil {
+		for _, obj := range *integ.Read.Objects {
+			names[obj.ObjectName] = true
+		}
+	}
+
+	return names
+}
+
+// declaresField tells whether the read object already has the field, as required or optional.
+func declaresField(integ *openapi.Integration, objName, fieldName string) bool {
+	for _, obj := range *integ.Read.Objects {
+		if obj.ObjectName != objName {
+			continue
+		}
+
+		for _, list := range []*[]openapi.IntegrationField{obj.RequiredFields, obj.OptionalFields} {
+			if list == nil {
+				continue
+			}
+
+			for _, field := range *list {
+				if strings.EqualFold(scaffold.FieldName(field), fieldName) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
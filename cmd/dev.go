@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/amp-labs/cli/internal/tunnel"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// devListenAddr and devSessionName stand in for 'amp listen's --listen and --name,
+	// which share variables with this command but have other defaults.
+	devListenAddr    string //nolint:gochecknoglobals
+	devSessionName   string //nolint:gochecknoglobals
+	keepDestinations bool   //nolint:gochecknoglobals
+
+	devCommand = &cobra.Command{ //nolint:gochecknoglobals
+		Use:   "dev",
+		Short: "Receive Ampersand webhooks in your local app",
+		Long: `Receive Ampersand webhooks in your local app, in one command.
+
+'amp dev' starts the webhook listener, waits for the public URL of your tunnel,
+points the destinations given by -D at it, and forwards every webhook to your app.
+On Ctrl+C, the listener stops and the destinations get their original URLs back
+(unless --keep-destinations is set).
+
+Start a tunnel to the listener's address (--listen) in another terminal:
+  ngrok http 127.0.0.1:4600
+  cloudflared tunnel --url http://127.0.0.1:4600 --metrics localhost:20241
+or pass a URL that already reaches it with --url.
+
+All 'amp listen' flags are supported, and the tunnel flags are those of 'amp tunnel sync'.
+
+Examples:
+  amp dev -D my-destination --forward-to http://localhost:4000/webhook
+  amp dev -D my-destination --provider cloudflared
+  amp dev -D my-destination --url https://dev.example.com -y`,
+		Hidden: true,
+		Args:   cobra.NoArgs,
+		RunE:   runDev,
+	}
+)
+
+func init() {
+	devCommand.Flags().StringVar(&devListenAddr, "listen", "127.0.0.1:4600",
+		"Address to listen on, which the tunnel must point at")
+	devCommand.Flags().StringVar(&devSessionName, "name", "dev",
+		"Session name, used by 'amp trigger --session' to find this listener")
+	addListenerFlags(devCommand)
+
+	devCommand.Flags().StringSliceVarP(&destinations, "destination", "D", []string{},
+		"Destination names or IDs (UUIDs)")
+	devCommand.Flags().BoolVarP(&skipConfirm, "yes", "y", false, "Skip confirmation prompts")
+	devCommand.Flags().BoolVar(&keepDestinations, "keep-destinations", false,
+		"Leave the destinations pointing at the tunnel when stopping")
+	devCommand.Flags().StringVar(&tunnelProvider, "provider", "",
+		"Tunnel provider: ngrok, cloudflared or static (default: static if --url is given, ngrok otherwise)")
+	devCommand.Flags().StringVarP(&ngrokServer, "ngrok-server", "s", "localhost:4040", "Ngrok server URL")
+	devCommand.Flags().StringVarP(&ngrokProtocol, "protocol", "P", "http",
+		"Protocol to use for ngrok server (http or https)")
+	devCommand.Flags().StringVar(&tunnelCloudflaredMetrics, "cloudflared-metrics", "localhost:20241",
+		"Address of cloudflared's metrics server")
+	devCommand.Flags().StringVar(&tunnelStaticURL, "url", "", "Public URL for the static provider")
+
+	rootCmd.AddCommand(devCommand)
+}
+
+func runDev(cmd *cobra.Command, _ []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	listenAddr = devListenAddr
+	sessionName = devSessionName
+
+	// Check everything that doesn't need the listener first, so that mistakes fail fast
+	provider, err := newTunnelProvider()
+	if err != nil {
+		return err
+	}
+
+	client, dests, err := setupNgrokExecution(ctx)
+	if err != nil {
+		return err
+	}
+
+	listener, err := startListener(ctx, cmd)
+	if err != nil {
+		return err
+	}
+
+	defer listener.registry.Unregister(listener.session)
+
+	printTunnelHint(provider, listener.session.URL())
+
+	chosen, err := getTunnel(ctx, provider)
+	if err != nil {
+		_ = listener.srv.Close()
+
+		// Ctrl+C while waiting for the tunnel is not an error, nothing was changed yet
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		return err
+	}
+
+	stats := updateDestinations(ctx, client, dests, chosen.PublicURL, true)
+	logDestinationStats(stats, len(dests))
+
+	fmt.Fprint(os.Stdout, "🌍 Webhooks sent to "+chosen.PublicURL+" now reach "+listener.session.URL()+"\n")
+	fmt.Fprint(os.Stdout, "Press Ctrl+C to stop\n")
+
+	err = listener.wait(ctx, stop)
+
+	if !keepDestinations {
+		// The API calls must outlive the interrupt that ended the session
+		restoreCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), restoreTimeout)
+		defer cancel()
+
+		restoreErr := restoreDestinations(restoreCtx, client, destinationIds(dests))
+		if err == nil {
+			err = restoreErr
+		}
+	}
+
+	return err
+}
+
+// printTunnelHint tells the user how to expose the listener with the chosen provider.
+func printTunnelHint(provider tunnel.Provider, listenerURL string) {
+	switch provider.Name() {
+	case tunnel.Ngrok:
+		fmt.Fprint(os.Stdout, "🚇 Start the tunnel with: ngrok http "+listenerURL+"\n")
+	case tunnel.Cloudflared:
+		fmt.Fprint(os.Stdout, "🚇 Start the tunnel with: cloudflared tunnel --url "+listenerURL+
+			" --metrics "+provider.AgentAddress()+"\n")
+	}
+}
@@ -0,0 +1,281 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/amp-labs/cli/internal/webhook"
+	"github.com/amp-labs/cli/logger"
+	"github.com/spf13/cobra"
+)
+
+var (
+	errNoReplayTarget = errors.New("event has no recorded forward target, please provide --forward-to")
+
+	eventsLimit     int
+	replayForwardTo []string
+	replayCAFile    string
+
+	eventsCommand = &cobra.Command{
+		Use:   "events",
+		Short: "Inspect and replay webhook events received by 'amp listen'",
+		Long: `Inspect and replay webhook events received by 'amp listen'.
+Every request the local listener receives is recorded, along with the result of
+forwarding it to your application, so that a real delivery can be replayed while
+you fix your handler.`,
+		Hidden: true,
+	}
+
+	eventsListCommand = &cobra.Command{
+		Use:   "list",
+		Short: "List recorded webhook events",
+		Args:  cobra.NoArgs,
+		RunE:  runEventsList,
+	}
+
+	eventsShowCommand = &cobra.Command{
+		Use:   "show <id>",
+		Short: "Show a recorded webhook event",
+		Args:  cobra.ExactArgs(1),
+		RunE:  runEventsShow,
+	}
+
+	eventsReplayCommand = &cobra.Command{
+		Use:   "replay <id>",
+		Short: "Replay a recorded webhook event",
+		Long: `Replay a recorded webhook event by sending the original body and headers again.
+By default the event is sent to every URL it was originally forwarded to.
+
+Examples:
+  amp events replay evt_20240101T120000_a1b2c3
+  amp events replay evt_20240101T120000 --forward-to http://localhost:4000/webhook`,
+		Args: cobra.ExactArgs(1),
+		RunE: runEventsReplay,
+	}
+)
+
+func init() {
+	eventsListCommand.Flags().IntVarP(&eventsLimit, "limit", "n", 0, "Only show the most recent N events")
+	eventsReplayCommand.Flags().StringArrayVar(&replayForwardTo, "forward-to", nil,
+		"URL to send the event to, can be repeated (default: the URLs it was originally forwarded to)")
+	eventsReplayCommand.Flags().StringVar(&replayCAFile, "forward-ca", "",
+		"PEM file with a CA to trust, in addition to the system's, when replaying to HTTPS targets")
+
+	eventsCommand.AddCommand(eventsListCommand)
+	eventsCommand.AddCommand(eventsShowCommand)
+	eventsCommand.AddCommand(eventsReplayCommand)
+	rootCmd.AddCommand(eventsCommand)
+}
+
+func runEventsList(cmd *cobra.Command, args []string) error {
+	store, err := webhook.DefaultStore()
+	if err != nil {
+		return err
+	}
+
+	events, err := store.List()
+	if err != nil {
+		return fmt.Errorf("failed to list events: %w", err)
+	}
+
+	if len(events) == 0 {
+		logger.Info("No events recorded yet, run 'amp listen' to start receiving events")
+
+		return nil
+	}
+
+	if eventsLimit > 0 && len(events) > eventsLimit {
+		events = events[len(events)-eventsLimit:]
+	}
+
+	for _, evt := range events {
+		logger.Info(strings.Join([]string{
+			evt.Id,
+			evt.ReceivedAt.Local().Format(time.RFC3339),
+			evt.Method,
+			evt.Path,
+			eventOutcome(evt),
+		}, "  "))
+	}
+
+	return nil
+}
+
+func runEventsShow(cmd *cobra.Command, args []string) error {
+	store, err := webhook.DefaultStore()
+	if err != nil {
+		return err
+	}
+
+	evt, err := store.Get(args[0])
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("ID:       %s", evt.Id)
+	logger.Infof("Received: %s", evt.ReceivedAt.Local().Format(time.RFC3339Nano))
+	logger.Infof("Request:  %s %s", evt.Method, evt.Path)
+
+	for _, result := range evt.Forwards {
+		logger.Infof("Forward:  %s → %s", result.URL, forwardSummary(result))
+	}
+
+	if evt.MockStatus != 0 {
+		logger.Infof("Response: %s (mock)", statusLine(evt.MockStatus))
+	}
+
+	for _, finding := range evt.ManifestFindings {
+		logger.Infof("Manifest: ⚠️  %s", finding)
+	}
+
+	logger.Info("\nHeaders:")
+
+	names := make([]string, 0, len(evt.Headers))
+	for name := range evt.Headers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, value := range evt.Headers[name] {
+			logger.Infof("  %s: %s", name, value)
+		}
+	}
+
+	logger.Info("\nBody:")
+	logger.Info(indentBody(evt.Body))
+
+	return nil
+}
+
+func runEventsReplay(cmd *cobra.Command, args []string) error {
+	store, err := webhook.DefaultStore()
+	if err != nil {
+		return err
+	}
+
+	evt, err := store.Get(args[0])
+	if err != nil {
+		return err
+	}
+
+	transport, err := newForwardTransport(replayCAFile)
+	if err != nil {
+		return err
+	}
+
+	targets, err := replayTargets(replayForwardTo, evt, transport)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprint(os.Stdout, "🔁 Replaying "+evt.Id+"\n")
+
+	for _, delivery := range webhook.ForwardAll(cmd.Context(), webhook.Route(targets, evt), evt, webhook.RetryPolicy{}) {
+		fmt.Fprint(os.Stdout, "  → "+delivery.Target.URL+" "+forwardSummary(delivery.Result)+"\n")
+
+		if delivery.Response != nil && len(delivery.Response.Body) > 0 {
+			logger.Info(indentBody(delivery.Response.Body))
+		}
+	}
+
+	return nil
+}
+
+// replayTargets returns the targets given by specs, or if there are none,
+// every target the event was originally forwarded to. All targets use transport.
+func replayTargets(specs []string, evt *webhook.Event, transport http.RoundTripper) ([]*webhook.Target, error) {
+	if len(specs) > 0 {
+		targets := make([]*webhook.Target, 0, len(specs))
+
+		for _, spec := range specs {
+			target, err := webhook.ParseTarget(spec, webhook.DefaultTimeout)
+			if err != nil {
+				return nil, err
+			}
+
+			target.Transport = transport
+			targets = append(targets, target)
+		}
+
+		return targets, nil
+	}
+
+	if len(evt.Forwards) == 0 {
+		return nil, errNoReplayTarget
+	}
+
+	targets := make([]*webhook.Target, 0, len(evt.Forwards))
+	for _, result := range evt.Forwards {
+		targets = append(targets, &webhook.Target{URL: result.URL, Timeout: webhook.DefaultTimeout, Transport: transport})
+	}
+
+	return targets, nil
+}
+
+// eventOutcome renders what happened to an event on one line.
+func eventOutcome(evt *webhook.Event) string {
+	if len(evt.Forwards) == 0 && evt.MockStatus != 0 {
+		return statusLine(evt.MockStatus) + " (mock)"
+	}
+
+	return forwardsSummary(evt.Forwards)
+}
+
+// forwardsSummary renders the outcome of all forwards of an event on one line.
+func forwardsSummary(results []*webhook.ForwardResult) string {
+	if len(results) == 0 {
+		return "not forwarded"
+	}
+
+	parts := make([]string, 0, len(results))
+	for _, result := range results {
+		parts = append(parts, forwardSummary(result))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// forwardSummary renders the outcome of a forward as e.g. "200 OK (12ms, 2 attempts)".
+func forwardSummary(result *webhook.ForwardResult) string {
+	if result == nil {
+		return "not forwarded"
+	}
+
+	details := result.Latency.Round(time.Millisecond).String()
+	if result.Attempts > 1 {
+		details += ", " + strconv.Itoa(result.Attempts) + " attempts"
+	}
+
+	if result.Error != "" {
+		return "failed (" + details + "): " + result.Error
+	}
+
+	return statusLine(result.StatusCode) + " (" + details + ")"
+}
+
+// statusLine renders a status code as e.g. "404 Not Found".
+func statusLine(code int) string {
+	return strconv.Itoa(code) + " " + http.StatusText(code)
+}
+
+// indentBody pretty-prints a JSON body, or returns it unchanged if it isn't JSON.
+func indentBody(body []byte) string {
+	var out bytes.Buffer
+
+	err := json.Indent(&out, body, "", "  ")
+	if err != nil {
+		return string(body)
+	}
+
+	return out.String()
+}
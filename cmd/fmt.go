@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/amp-labs/cli/internal/scaffold"
+	"github.com/amp-labs/cli/logger"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/spf13/cobra"
+)
+
+var fmtCheck bool //nolint:gochecknoglobals
+
+var fmtCmd = &cobra.Command{ //nolint:gochecknoglobals
+	Use:   "fmt [manifest...]",
+	Short: "Format amp.yaml files",
+	Long: `Rewrite manifests in the canonical layout, so that they look the same whoever wrote them.
+
+Keys follow the order of the manifest schema, mappings are indented by two spaces and
+sequences are not indented, the way 'amp init' writes them. Comments are kept.
+
+The manifests are amp.yaml by default, and a directory stands for the amp.yaml in it.
+With --check, the files are left as they are: the changes are printed as a diff, and the
+command fails if there are any, which suits CI.`,
+	Hidden: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			args = []string{"amp.yaml"}
+		}
+
+		unformatted := 0
+
+		for _, path := range args {
+			changed, err := formatManifest(path)
+			if err != nil {
+				logger.FatalErr("Unable to format "+path, err)
+			}
+
+			if changed {
+				unformatted++
+			}
+		}
+
+		if fmtCheck && unformatted > 0 {
+			fmt.Fprintf(os.Stdout, "%d manifest(s) are not formatted, run 'amp fmt' to fix them\n", unformatted)
+			os.Exit(1)
+		}
+	},
+}
+
+// formatManifest formats the manifest at the path, or prints the diff with --check. It tells
+// whether the manifest was not formatted.
+func formatManifest(path string) (bool, error) {
+	path = manifestPath(path)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+
+	formatted, err := scaffold.Format(data)
+	if err != nil {
+		return false, err
+	}
+
+	if bytes.Equal(data, formatted) {
+		return false, nil
+	}
+
+	if fmtCheck {
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(string(data)),
+			B:        difflib.SplitLines(string(formatted)),
+			FromFile: path,
+			ToFile:   path + " (formatted)",
+			Context:  3, //nolint:mnd
+		})
+		if err != nil {
+			return false, fmt.Errorf("failed to diff: %w", err)
+		}
+
+		fmt.Fprint(os.Stdout, diff)
+
+		return true, nil
+	}
+
+	err = os.WriteFile(path, formatted, YamlFileMode) //nolint:gosec
+	if err != nil {
+		return false, err
+	}
+
+	fmt.Fprintf(os.Stdout, "Formatted %s\n", path)
+
+	return true, nil
+}
+
+// manifestPath returns the path of a manifest given as a file or as the directory holding amp.yaml.
+func manifestPath(path string) string {
+	if st, err := os.Stat(path); err == nil && st.IsDir() {
+		return filepath.Join(path, "amp.yaml")
+	}
+
+	return path
+}
+
+func init() {
+	fmtCmd.Flags().BoolVar(&fmtCheck, "check", false, "Print a diff and fail instead of rewriting unformatted manifests")
+	rootCmd.AddCommand(fmtCmd)
+}
@@ -5,11 +5,15 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 
+	"github.com/amp-labs/cli/files"
 	"github.com/amp-labs/cli/flags"
+	"github.com/amp-labs/cli/internal/scaffold"
+	"github.com/amp-labs/cli/internal/schedule"
 	"github.com/amp-labs/cli/logger"
 	"github.com/amp-labs/cli/openapi"
 	"github.com/amp-labs/cli/request"
@@ -35,12 +39,118 @@ func nonEmpty(fieldName string) func(string) error {
 	}
 }
 
+// validSchedule accepts cron schedules, and empty ones.
+func validSchedule(expr string) error {
+	if expr == "" {
+		return nil
+	}
+
+	_, err := schedule.Parse(expr)
+
+	return err //nolint:wrapcheck
+}
+
+var (
+	ErrUnknownProvider = errors.New("unknown provider")
+
+	initAnswersFile string   //nolint:gochecknoglobals
+	initName        string   //nolint:gochecknoglobals
+	initDisplayName string   //nolint:gochecknoglobals
+	initProvider    string   //nolint:gochecknoglobals
+	initModule      string   //nolint:gochecknoglobals
+	initRead        []string //nolint:gochecknoglobals
+	initWrite       []string //nolint:gochecknoglobals
+	initProxy       bool     //nolint:gochecknoglobals
+	initDestination string   //nolint:gochecknoglobals
+	initSchedule    string   //nolint:gochecknoglobals
+	initSubscribe   []string //nolint:gochecknoglobals
+	initAssocChange bool     //nolint:gochecknoglobals
+	initWatchSchema bool     //nolint:gochecknoglobals
+
+	// initAnswersFlags describe the integration instead of the prompts, like --answers does.
+	initAnswersFlags = []string{ //nolint:gochecknoglobals
+		"name", "display-name", "provider", "module", "read", "write", "proxy", "destination", "schedule",
+		"subscribe", "association-change", "watch-schema",
+	}
+)
+
 var initCmd = &cobra.Command{ //nolint:gochecknoglobals
-	Use:    "init",
-	Short:  "initialize an Ampersand manifest file",
-	Long:   "Initialize an Ampersand manifest file.",
+	Use:   "init",
+	Short: "initialize an Ampersand manifest file",
+	Long: `Initialize an Ampersand manifest file.
+
+Without flags, the integration is set up through interactive prompts. To script it,
+describe the integration with flags, or in an answers file:
+
+  amp init --name x --provider salesforce --module crm --read Account,Contact --destination myWebhook \
+    --schedule "*/10 * * * *" --write Lead --proxy --subscribe Account --watch-schema
+  amp init --answers answers.yaml
+
+An answers file has the same shape as the prompts, for example:
+
+  name: x
+  provider: salesforce
+  module: crm
+  read:
+    - objectName: Account
+      destination: myWebhook
+      schedule: "*/10 * * * *"
+      backfill: {days: 30}
+      requiredFields: [Id, Name]
+      optionalFields:
+        - Industry
+        - {fieldName: AnnualRevenue, mapped: true, displayName: Revenue}
+      allowUserFields: true
+  write:
+    - objectName: Lead
+  proxy: true
+  subscribe:
+    - objectName: Account
+      destination: myWebhook
+      inheritFieldsAndMapping: true
+      create: true
+      update: true
+      watchFields: [Name]
+      watchFieldsAuto: selected
+      delete: true
+      associationChange: true
+  watchSchema:
+    destination: myWebhook
+    schedule: "0 * * * *"
+    fieldCreated: true
+    fieldDeleted: true
+    fieldChanged: true
+
+The module only needs to be given for providers that have several, and defaults to
+the provider's default module.
+
+With flags, --subscribe subscribes to every event the provider supports, and
+--watch-schema reports every kind of field change, both to --destination.
+
+Fields are picked from the provider's objects, which are read through a connection of
+the project (see --group-ref) or from --fields-file. The fields of answers files are
+checked against them, to catch typos.
+
+A template starts the manifest from a common pattern, filled in for the provider:
+
+  amp init --template crm-contacts-sync --provider hubspot --set object=contacts
+  amp init --list-templates
+
+Templates are manifests written in mustache. Your own go in the ampersand/templates
+directory of your config directory (e.g. ~/.config/ampersand/templates), or in the
+directories given by --template-dir, and replace the built-in ones of the same name.
+--name, --display-name, --module, --destination and --schedule also apply to templates.`,
 	Hidden: true,
 	Run: func(cmd *cobra.Command, args []string) {
+		if initListTemplates {
+			err := listTemplates()
+			if err != nil {
+				logger.FatalErr("Unable to list templates", err)
+			}
+
+			return
+		}
+
 		st, err := os.Stat("amp.yaml")
 		if err != nil {
 			if os.IsNotExist(err) {
@@ -54,90 +164,255 @@ var initCmd = &cobra.Command{ //nolint:gochecknoglobals
 			logger.Fatal("amp.yaml already exists, please remove it before running this command")
 		}
 
-		name, err := promptString("Name your integration", nonEmpty("Integration name"))
-		if err != nil {
-			logger.FatalErr("Unable to prompt for integration name", err)
+		if initTemplate != "" {
+			ys, err := renderTemplate(cmd.Context())
+			if err != nil {
+				logger.FatalErr("Unable to fill in the template", err)
+			}
+
+			writeInitManifest(ys)
+
+			return
 		}
 
-		provider, err := selectProvider(cmd.Context())
+		answers, provider, err := getInitAnswers(cmd)
 		if err != nil {
-			logger.FatalErr("Unable to select provider", err)
+			logger.FatalErr("Unable to set up the integration", err)
 		}
 
-		disp := "my integration"
-
-		var integ openapi.Integration
+		integ, err := answers.Integration(provider)
+		if err != nil {
+			logger.FatalErr("Unable to build the integration", err)
+		}
 
-		integ.Name = name
-		integ.DisplayName = disp
-		integ.Provider = provider.Name
+		manifest := scaffold.Manifest(integ)
 
-		if provider.Support.Read {
-			err := setupRead(&integ, provider)
+		// Scripts get a manifest that can be deployed as is, or an error
+		if !interactiveInit(cmd) {
+			err := files.ValidateManifest(manifest)
 			if err != nil {
-				logger.FatalErr("Unable to setup read", err)
+				logger.FatalErr("The answers don't make a valid manifest", err)
 			}
-		}
 
-		if provider.Support.Write {
-			err := setupWrite(&integ, provider)
+			err = checkFieldNames(cmd.Context(), &integ)
 			if err != nil {
-				logger.FatalErr("Unable to setup write", err)
+				logger.FatalErr("The answers don't make a valid manifest", err)
 			}
 		}
 
-		if provider.Support.Proxy {
-			err := setupProxy(&integ, provider)
-			if err != nil {
-				logger.FatalErr("Unable to setup proxy", err)
-			}
+		ys, err := yaml.Marshal(manifest)
+		if err != nil {
+			logger.FatalErr("Unable to marshal manifest", err)
 		}
 
-		// TODO: Add an endpoint to the API to fetch the latest spec version.
-		// If the spec version from the server is newer, we want to block
-		// the user from running this command until they upgrade.
-		manifest := &openapi.Manifest{
-			SpecVersion: "1.0.0",
-			Integrations: []openapi.Integration{
-				integ,
-			},
+		writeInitManifest(ys)
+	},
+}
+
+func writeInitManifest(ys []byte) {
+	err := os.WriteFile("amp.yaml", ys, YamlFileMode) //nolint:gosec
+	if err != nil {
+		logger.FatalErr("Unable to write manifest to file", err)
+	}
+
+	fmt.Fprint(os.Stdout, "Integration manifest written to amp.yaml\n")
+}
+
+// interactiveInit tells whether the integration is to be set up through prompts, which is
+// the case unless it is described by flags or an answers file.
+func interactiveInit(cmd *cobra.Command) bool {
+	if cmd.Flags().Changed("answers") {
+		return false
+	}
+
+	for _, name := range initAnswersFlags {
+		if cmd.Flags().Changed(name) {
+			return false
 		}
+	}
 
-		ys, err := yaml.Marshal(manifest)
+	return true
+}
+
+// getInitAnswers gets the answers from the prompts, the flags or the answers file, along
+// with the catalog entry of the chosen provider.
+func getInitAnswers(cmd *cobra.Command) (*scaffold.Answers, *openapi.ProviderInfo, error) {
+	if interactiveInit(cmd) {
+		return promptAnswers(cmd.Context())
+	}
+
+	answers := &scaffold.Answers{Provider: initProvider}
+
+	if initAnswersFile != "" {
+		var err error
+
+		answers, err = scaffold.ReadAnswers(initAnswersFile)
 		if err != nil {
-			logger.FatalErr("Unable to marshal manifest", err)
+			return nil, nil, err
 		}
+	}
+
+	if answers.Provider == "" {
+		return nil, nil, scaffold.ErrMissingProvider
+	}
+
+	provider, err := lookupProvider(cmd.Context(), answers.Provider)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = useFieldSource(cmd.Context(), provider.Name)
+	if err != nil {
+		return nil, nil, err
+	}
 
-		err = os.WriteFile("amp.yaml", ys, YamlFileMode) //nolint:gosec
+	if initAnswersFile == "" {
+		// The events to subscribe to depend on the module's support
+		module, err := scaffold.ModuleProvider(provider, initModule)
 		if err != nil {
-			logger.FatalErr("Unable to write manifest to file", err)
+			return nil, nil, err
 		}
 
-		fmt.Fprint(os.Stdout, "Integration manifest written to amp.yaml\n")
-	},
+		answers = answersFromFlags(module)
+	}
+
+	return answers, provider, nil
 }
 
-func addWriteObject(write *openapi.IntegrationWrite, provider *openapi.ProviderInfo) error {
-	obj := &openapi.IntegrationWriteObject{}
+// answersFromFlags describes the integration given by the flags of 'amp init'. The provider
+// has the support of the chosen module.
+func answersFromFlags(provider *openapi.ProviderInfo) *scaffold.Answers {
+	answers := &scaffold.Answers{
+		Name:        initName,
+		DisplayName: initDisplayName,
+		Provider:    initProvider,
+		Module:      initModule,
+		Proxy:       initProxy,
+	}
 
-	objName, err := promptString(provider.DisplayName + " object name")
+	for _, objName := range initRead {
+		answers.Read = append(answers.Read, scaffold.ReadObject{
+			ObjectName:  objName,
+			Destination: initDestination,
+			Schedule:    initSchedule,
+		})
+	}
+
+	for _, objName := range initWrite {
+		answers.Write = append(answers.Write, scaffold.WriteObject{ObjectName: objName})
+	}
+
+	events := scaffold.SupportedEvents(provider.Support)
+
+	for _, objName := range initSubscribe {
+		answers.Subscribe = append(answers.Subscribe, scaffold.SubscribeObject{
+			ObjectName:              objName,
+			Destination:             initDestination,
+			InheritFieldsAndMapping: slices.Contains(initRead, objName),
+			Create:                  events.Create,
+			Update:                  events.Update,
+			Delete:                  events.Delete,
+			AssociationChange:       initAssocChange,
+		})
+	}
+
+	if initWatchSchema {
+		answers.WatchSchema = &scaffold.WatchSchema{
+			Destination:  initDestination,
+			FieldCreated: true,
+			FieldDeleted: true,
+			FieldChanged: true,
+		}
+	}
+
+	return answers
+}
+
+// promptAnswers asks the user about the integration.
+func promptAnswers(ctx context.Context) (*scaffold.Answers, *openapi.ProviderInfo, error) {
+	name, err := promptString("Name your integration", nonEmpty("Integration name"))
 	if err != nil {
-		return err
+		return nil, nil, fmt.Errorf("unable to prompt for integration name: %w", err)
 	}
 
-	obj.ObjectName = objName
+	provider, err := selectProvider(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to select provider: %w", err)
+	}
 
-	if write.Objects == nil {
-		tmp := make([]openapi.IntegrationWriteObject, 0)
-		write.Objects = &tmp
+	err = useFieldSource(ctx, provider.Name)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	*write.Objects = append(*write.Objects, *obj)
+	moduleName, err := selectModule(provider)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to select module: %w", err)
+	}
+
+	answers := &scaffold.Answers{
+		Name:     name,
+		Provider: provider.Name,
+		Module:   moduleName,
+	}
+
+	// The actions on offer are those of the module
+	module, err := scaffold.ModuleProvider(provider, moduleName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if module.Support.Read {
+		err := setupRead(ctx, answers, module)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to setup read: %w", err)
+		}
+	}
+
+	if module.Support.Write {
+		err := setupWrite(answers, module)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to setup write: %w", err)
+		}
+	}
+
+	if module.Support.Proxy {
+		err := setupProxy(answers, module)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to setup proxy: %w", err)
+		}
+	}
+
+	if module.Support.Subscribe {
+		err := setupSubscribe(ctx, answers, module)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to setup subscribe: %w", err)
+		}
+	}
+
+	// Schema changes are reported for the objects of the integration, so there must be some
+	if len(answers.Read) > 0 || len(answers.Subscribe) > 0 {
+		err := setupWatchSchema(answers, module)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to setup schema watching: %w", err)
+		}
+	}
+
+	return answers, provider, nil
+}
+
+func addWriteObject(answers *scaffold.Answers, provider *openapi.ProviderInfo) error {
+	objName, err := promptString(provider.DisplayName + " object name")
+	if err != nil {
+		return err
+	}
+
+	answers.Write = append(answers.Write, scaffold.WriteObject{ObjectName: objName})
 
 	return nil
 }
 
-func setupWrite(integ *openapi.Integration, provider *openapi.ProviderInfo) error {
+func setupWrite(answers *scaffold.Answers, provider *openapi.ProviderInfo) error {
 	wantWrite, err := promptBool("Enable write support for " + provider.DisplayName)
 	if err != nil {
 		return err
@@ -147,10 +422,8 @@ func setupWrite(integ *openapi.Integration, provider *openapi.ProviderInfo) erro
 		return nil
 	}
 
-	write := &openapi.IntegrationWrite{}
-
 	for {
-		err := addWriteObject(write, provider)
+		err := addWriteObject(answers, provider)
 		if err != nil {
 			return err
 		}
@@ -165,23 +438,17 @@ func setupWrite(integ *openapi.Integration, provider *openapi.ProviderInfo) erro
 		}
 	}
 
-	integ.Write = write
-
 	return nil
 }
 
-func setupBackfill(obj *openapi.IntegrationObject) error {
+func setupBackfill(obj *scaffold.ReadObject) error {
 	full, err := promptBool("Backfill full history for " + obj.ObjectName)
 	if err != nil {
 		return err
 	}
 
 	if full {
-		obj.Backfill = &openapi.Backfill{
-			DefaultPeriod: openapi.DefaultPeriod{
-				FullHistory: &full,
-			},
-		}
+		obj.Backfill = &scaffold.Backfill{FullHistory: true}
 
 		return nil
 	}
@@ -192,42 +459,33 @@ func setupBackfill(obj *openapi.IntegrationObject) error {
 	}
 
 	if days > 0 {
-		tmp := int(days)
-		obj.Backfill = &openapi.Backfill{
-			DefaultPeriod: openapi.DefaultPeriod{
-				Days: &tmp,
-			},
-		}
+		obj.Backfill = &scaffold.Backfill{Days: int(days)}
 	}
 
 	return nil
 }
 
-func getIntegrationField() (*openapi.IntegrationField, error) { //nolint:funlen,cyclop
-	field := &openapi.IntegrationField{}
-
-	fieldName, err := promptString("Field name")
+// getIntegrationField asks about a field, picked among the fields of the object if they are known.
+func getIntegrationField(fields []openapi.FieldMetadata) (*scaffold.Field, error) { //nolint:funlen,cyclop
+	fieldName, err := promptFieldName("Field name", fields)
 	if err != nil {
 		return nil, err
 	}
 
+	field := &scaffold.Field{FieldName: fieldName}
+
 	remap, err := promptBool("Do you want to let users choose a different name for this field")
 	if err != nil {
 		return nil, err
 	}
 
 	if !remap {
-		err := field.FromIntegrationFieldExistent(openapi.IntegrationFieldExistent{
-			FieldName: fieldName,
-		})
-		if err != nil {
-			return nil, err
-		}
-
 		return field, nil
 	}
 
-	displayName, err := promptString("Friendly display name for " + fieldName)
+	field.Mapped = true
+
+	field.DisplayName, err = promptString("Friendly display name for " + fieldName)
 	if err != nil {
 		return nil, err
 	}
@@ -258,30 +516,20 @@ func getIntegrationField() (*openapi.IntegrationField, error) { //nolint:funlen,
 		}
 	}
 
-	mapping := openapi.IntegrationFieldMapping{
-		MapToName:        fieldName,
-		MapToDisplayName: &displayName,
-	}
-
 	if wantDefault {
-		mapping.Default = &defaultValue
+		field.Default = &defaultValue
 	}
 
 	if wantPrompt {
-		mapping.Prompt = &promptText
-	}
-
-	err = field.FromIntegrationFieldMapping(mapping)
-	if err != nil {
-		return nil, err
+		field.Prompt = &promptText
 	}
 
 	return field, nil
 }
 
 //nolint:funlen,cyclop,gocognit
-func addReadObject(read *openapi.IntegrationRead, provider *openapi.ProviderInfo) error {
-	obj := &openapi.IntegrationObject{}
+func addReadObject(ctx context.Context, answers *scaffold.Answers, provider *openapi.ProviderInfo) error {
+	obj := &scaffold.ReadObject{}
 
 	objName, err := promptString(provider.DisplayName + " object name")
 	if err != nil {
@@ -297,12 +545,12 @@ func addReadObject(read *openapi.IntegrationRead, provider *openapi.ProviderInfo
 
 	obj.Destination = destination
 
-	schedule, err := promptString("Cron schedule for " + objName)
+	obj.Schedule, err = promptString("Cron schedule for "+objName, validSchedule)
 	if err != nil {
 		return err
 	}
 
-	obj.Schedule = schedule
+	fields := objectFields(ctx, objName)
 
 	wantBackfill, err := promptBool("Configure backfill period for " + objName)
 	if err != nil {
@@ -341,17 +589,12 @@ func addReadObject(read *openapi.IntegrationRead, provider *openapi.ProviderInfo
 
 		counter++
 
-		field, err := getIntegrationField()
+		field, err := getIntegrationField(fields)
 		if err != nil {
 			return err
 		}
 
-		if obj.RequiredFields == nil {
-			tmp := make([]openapi.IntegrationField, 0)
-			obj.RequiredFields = &tmp
-		}
-
-		*obj.RequiredFields = append(*obj.RequiredFields, *field)
+		obj.RequiredFields = append(obj.RequiredFields, *field)
 	}
 
 	counter = 0
@@ -379,17 +622,12 @@ func addReadObject(read *openapi.IntegrationRead, provider *openapi.ProviderInfo
 
 		counter++
 
-		field, err := getIntegrationField()
+		field, err := getIntegrationField(fields)
 		if err != nil {
 			return err
 		}
 
-		if obj.OptionalFields == nil {
-			tmp := make([]openapi.IntegrationField, 0)
-			obj.OptionalFields = &tmp
-		}
-
-		*obj.OptionalFields = append(*obj.OptionalFields, *field)
+		obj.OptionalFields = append(obj.OptionalFields, *field)
 	}
 
 	wantOptionalAuto, err := promptBool("Allow users to add their own optional fields for " + objName)
@@ -397,22 +635,14 @@ func addReadObject(read *openapi.IntegrationRead, provider *openapi.ProviderInfo
 		return err
 	}
 
-	if wantOptionalAuto {
-		tmp := openapi.OptionalFieldsAutoOptionAll
-		obj.OptionalFieldsAuto = &tmp
-	}
-
-	if read.Objects == nil {
-		tmp := make([]openapi.IntegrationObject, 0)
-		read.Objects = &tmp
-	}
+	obj.AllowUserFields = wantOptionalAuto
 
-	*read.Objects = append(*read.Objects, *obj)
+	answers.Read = append(answers.Read, *obj)
 
 	return nil
 }
 
-func setupRead(integ *openapi.Integration, provider *openapi.ProviderInfo) error {
+func setupRead(ctx context.Context, answers *scaffold.Answers, provider *openapi.ProviderInfo) error {
 	wantRead, err := promptBool("Enable read support for " + provider.DisplayName)
 	if err != nil {
 		return err
@@ -422,10 +652,48 @@ func setupRead(integ *openapi.Integration, provider *openapi.ProviderInfo) error
 		return nil
 	}
 
-	read := &openapi.IntegrationRead{}
+	for {
+		err := addReadObject(ctx, answers, provider)
+		if err != nil {
+			return err
+		}
+
+		another, err := promptBool("Add another object")
+		if err != nil {
+			return err
+		}
+
+		if !another {
+			break
+		}
+	}
+
+	return nil
+}
+
+func setupProxy(answers *scaffold.Answers, provider *openapi.ProviderInfo) error {
+	wantProxy, err := promptBool("Enable proxy support for " + provider.DisplayName)
+	if err != nil {
+		return err
+	}
+
+	answers.Proxy = wantProxy
+
+	return nil
+}
+
+func setupSubscribe(ctx context.Context, answers *scaffold.Answers, provider *openapi.ProviderInfo) error {
+	wantSubscribe, err := promptBool("Enable subscribe support for " + provider.DisplayName)
+	if err != nil {
+		return err
+	}
+
+	if !wantSubscribe {
+		return nil
+	}
 
 	for {
-		err := addReadObject(read, provider)
+		err := addSubscribeObject(ctx, answers, provider)
 		if err != nil {
 			return err
 		}
@@ -440,22 +708,150 @@ func setupRead(integ *openapi.Integration, provider *openapi.ProviderInfo) error
 		}
 	}
 
-	integ.Read = read
+	return nil
+}
+
+//nolint:funlen,cyclop
+func addSubscribeObject(ctx context.Context, answers *scaffold.Answers, provider *openapi.ProviderInfo) error {
+	objName, err := promptString(provider.DisplayName+" object name", nonEmpty("Object name"))
+	if err != nil {
+		return err
+	}
+
+	obj := scaffold.SubscribeObject{ObjectName: objName}
+
+	obj.Destination, err = promptString("Destination for " + objName + " events")
+	if err != nil {
+		return err
+	}
+
+	if slices.ContainsFunc(answers.Read, func(read scaffold.ReadObject) bool { return read.ObjectName == objName }) {
+		obj.InheritFieldsAndMapping, err = promptBool("Use the fields and mapping of the read object " + objName)
+		if err != nil {
+			return err
+		}
+	}
+
+	events := scaffold.SupportedEvents(provider.Support)
+
+	if events.Create {
+		obj.Create, err = promptBool("Subscribe to create events for " + objName)
+		if err != nil {
+			return err
+		}
+	}
+
+	if events.Update {
+		obj.Update, err = promptBool("Subscribe to update events for " + objName)
+		if err != nil {
+			return err
+		}
+	}
+
+	if obj.Update {
+		err := setupWatchFields(ctx, &obj)
+		if err != nil {
+			return err
+		}
+	}
+
+	if events.Delete {
+		obj.Delete, err = promptBool("Subscribe to delete events for " + objName)
+		if err != nil {
+			return err
+		}
+	}
+
+	obj.AssociationChange, err = promptBool("Subscribe to association change events for " + objName)
+	if err != nil {
+		return err
+	}
+
+	if obj.AssociationChange {
+		obj.IncludeFullRecords, err = promptBool("Include the full records in association change events")
+		if err != nil {
+			return err
+		}
+	}
+
+	answers.Subscribe = append(answers.Subscribe, obj)
 
 	return nil
 }
 
-func setupProxy(integ *openapi.Integration, provider *openapi.ProviderInfo) error {
-	wantProxy, err := promptBool("Enable proxy support for " + provider.DisplayName)
+// setupWatchFields asks which field changes trigger update events.
+func setupWatchFields(ctx context.Context, obj *scaffold.SubscribeObject) error {
+	watchAll, err := promptBool("Send update events when any field of " + obj.ObjectName + " changes")
 	if err != nil {
 		return err
 	}
 
-	if wantProxy {
-		integ.Proxy = &openapi.IntegrationProxy{
-			Enabled: &wantProxy,
+	if watchAll {
+		obj.WatchFieldsAuto = string(openapi.UpdateEventWatchFieldsAutoAll)
+
+		return nil
+	}
+
+	obj.WatchFieldsAuto = string(openapi.UpdateEventWatchFieldsAutoSelected)
+	fields := objectFields(ctx, obj.ObjectName)
+
+	for {
+		wantField, err := promptBool("Add a field that always triggers update events for " + obj.ObjectName)
+		if err != nil {
+			return err
 		}
+
+		if !wantField {
+			return nil
+		}
+
+		field, err := promptFieldName("Field name", fields)
+		if err != nil {
+			return err
+		}
+
+		obj.WatchFields = append(obj.WatchFields, field)
 	}
+}
+
+func setupWatchSchema(answers *scaffold.Answers, provider *openapi.ProviderInfo) error {
+	wantWatch, err := promptBool("Notify about changes to " + provider.DisplayName + " fields")
+	if err != nil {
+		return err
+	}
+
+	if !wantWatch {
+		return nil
+	}
+
+	schema := &scaffold.WatchSchema{}
+
+	schema.Destination, err = promptString("Destination for schema change notifications", nonEmpty("Destination"))
+	if err != nil {
+		return err
+	}
+
+	schema.Schedule, err = promptString("Cron schedule for schema checks (at most hourly, empty for daily)",
+		validSchedule)
+	if err != nil {
+		return err
+	}
+
+	for _, change := range []struct {
+		label string
+		value *bool
+	}{
+		{"Notify when fields are created", &schema.FieldCreated},
+		{"Notify when fields are deleted", &schema.FieldDeleted},
+		{"Notify when fields are changed", &schema.FieldChanged},
+	} {
+		*change.value, err = promptBool(change.label)
+		if err != nil {
+			return err
+		}
+	}
+
+	answers.WatchSchema = schema
 
 	return nil
 }
@@ -525,14 +921,39 @@ func promptInt(prompt string) (int64, error) {
 	return ival, nil
 }
 
-func selectProvider(ctx context.Context) (*openapi.ProviderInfo, error) {
+// getCatalog fetches the provider catalog.
+func getCatalog(ctx context.Context) (openapi.CatalogType, error) {
 	apiKey := flags.GetAPIKey()
 
 	client := request.NewAPIClient("ignore", &apiKey)
 
 	cat, err := client.GetCatalog(ctx)
 	if err != nil {
-		logger.FatalErr("Unable to get catalog", err)
+		return nil, fmt.Errorf("unable to get catalog: %w", err)
+	}
+
+	return cat, nil
+}
+
+// lookupProvider returns the catalog entry of a provider, given its name.
+func lookupProvider(ctx context.Context, name string) (*openapi.ProviderInfo, error) {
+	cat, err := getCatalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	provider, ok := cat[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownProvider, name)
+	}
+
+	return &provider, nil
+}
+
+func selectProvider(ctx context.Context) (*openapi.ProviderInfo, error) {
+	cat, err := getCatalog(ctx)
+	if err != nil {
+		return nil, err
 	}
 
 	providers := make([]openapi.ProviderInfo, 0, len(cat))
@@ -574,6 +995,57 @@ func selectProvider(ctx context.Context) (*openapi.ProviderInfo, error) {
 	return &providers[idx], nil
 }
 
+// selectModule asks which module of the provider to use, if there is a choice. The default
+// module comes first.
+func selectModule(provider *openapi.ProviderInfo) (string, error) {
+	names := scaffold.ModuleNames(provider)
+	if len(names) < 2 { //nolint:mnd
+		return "", nil
+	}
+
+	labels := make([]string, 0, len(names))
+	for _, name := range names {
+		labels = append(labels, (*provider.Modules)[name].DisplayName+" ("+name+")")
+	}
+
+	prompt := promptui.Select{
+		Label:  "Select a " + provider.DisplayName + " module",
+		Items:  labels,
+		Stdin:  os.Stdin,
+		Stdout: os.Stdout,
+	}
+
+	idx, _, err := prompt.Run()
+	if err != nil {
+		return "", err
+	}
+
+	return names[idx], nil
+}
+
 func init() {
+	initCmd.Flags().StringVar(&initAnswersFile, "answers", "", "YAML file with the answers to the setup questions")
+	initCmd.Flags().StringVar(&initName, "name", "", "Name of the integration")
+	initCmd.Flags().StringVar(&initDisplayName, "display-name", "", "Display name of the integration")
+	initCmd.Flags().StringVar(&initProvider, "provider", "", "Provider of the integration, e.g. salesforce")
+	initCmd.Flags().StringVar(&initModule, "module", "",
+		"Module of the provider, e.g. crm (default: the provider's default module)")
+	initCmd.Flags().StringSliceVar(&initRead, "read", nil, "Objects to read (comma-separated)")
+	initCmd.Flags().StringSliceVar(&initWrite, "write", nil, "Objects to write (comma-separated)")
+	initCmd.Flags().BoolVar(&initProxy, "proxy", false, "Enable proxy support")
+	initCmd.Flags().StringVar(&initDestination, "destination", "", "Destination of the objects given by --read")
+	initCmd.Flags().StringVar(&initSchedule, "schedule", "", "Cron schedule of the objects given by --read")
+	initCmd.Flags().StringSliceVar(&initSubscribe, "subscribe", nil, "Objects to subscribe to (comma-separated)")
+	initCmd.Flags().BoolVar(&initAssocChange, "association-change", false,
+		"Subscribe to association change events of the objects given by --subscribe")
+	initCmd.Flags().BoolVar(&initWatchSchema, "watch-schema", false, "Notify --destination about field changes")
+
+	addFieldSourceFlags(initCmd)
+	addTemplateFlags(initCmd)
+
+	for _, name := range initAnswersFlags {
+		initCmd.MarkFlagsMutuallyExclusive("answers", name)
+	}
+
 	rootCmd.AddCommand(initCmd)
 }
@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/amp-labs/cli/flags"
+	"github.com/amp-labs/cli/internal/metadata"
+	"github.com/amp-labs/cli/logger"
+	"github.com/amp-labs/cli/openapi"
+	"github.com/amp-labs/cli/request"
+	"github.com/manifoldco/promptui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	errUnknownFields = errors.New("the provider doesn't have some of the fields")
+
+	fieldsFile     string //nolint:gochecknoglobals
+	fieldsGroupRef string //nolint:gochecknoglobals
+
+	// fieldSource is where field pickers get their fields from, nil if field names are to be typed in.
+	fieldSource metadata.Source //nolint:gochecknoglobals
+)
+
+// addFieldSourceFlags adds the flags that say where to find the fields of provider objects.
+func addFieldSourceFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&fieldsFile, "fields-file", "",
+		"JSON file with the fields of the provider's objects, instead of getting them from the API")
+	cmd.Flags().StringVar(&fieldsGroupRef, "group-ref", "",
+		"Group whose connection to the provider is used to get the fields of objects "+
+			"(default: the first group connected to the provider)")
+}
+
+// useFieldSource sets up where the fields of the provider's objects come from: the file given
+// by --fields-file, or else the API, through a connection to the provider. Without a project or
+// a connection, field names are typed in instead.
+func useFieldSource(ctx context.Context, provider string) error {
+	fieldSource = nil
+
+	if fieldsFile != "" {
+		file, err := metadata.ReadFile(fieldsFile)
+		if err != nil {
+			return err
+		}
+
+		fieldSource = file
+
+		return nil
+	}
+
+	project := flags.GetProject()
+	if project == "" {
+		logger.Debug("No project given, field names will be typed in")
+
+		return nil
+	}
+
+	apiKey := flags.GetAPIKey()
+	client := request.NewAPIClient(project, &apiKey)
+
+	groupRef := fieldsGroupRef
+	if groupRef == "" {
+		conns, err := client.ListConnections(ctx)
+		if err != nil {
+			logger.Debugf("Unable to list connections, field names will be typed in: %v", err)
+
+			return nil
+		}
+
+		idx := slices.IndexFunc(conns, func(conn *request.Connection) bool {
+			return conn.Provider == provider && conn.Group != nil
+		})
+		if idx < 0 {
+			logger.Debugf("No connection to %s, field names will be typed in", provider)
+
+			return nil
+		}
+
+		groupRef = conns[idx].Group.GroupRef
+	}
+
+	fieldSource = metadata.NewAPI(client, provider, groupRef)
+
+	return nil
+}
+
+// objectFields returns the fields of the object, or nil if they can't be found.
+func objectFields(ctx context.Context, objName string) []openapi.FieldMetadata {
+	if fieldSource == nil {
+		return nil
+	}
+
+	fields, err := fieldSource.Fields(ctx, objName)
+	if err != nil {
+		logger.Infof("The fields of %s are unknown, please type them in (%v)", objName, err)
+
+		return nil
+	}
+
+	return fields
+}
+
+// checkFieldNames fails if the read objects of the integration have fields that the provider
+// doesn't, which catches typos in answers files.
+func checkFieldNames(ctx context.Context, integ *openapi.Integration) error {
+	if fieldSource == nil {
+		return nil
+	}
+
+	unknown, err := metadata.CheckFields(ctx, fieldSource, integ)
+	if err != nil {
+		return err
+	}
+
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	msgs := make([]string, 0, len(unknown))
+	for _, field := range unknown {
+		msgs = append(msgs, field.String())
+	}
+
+	return fmt.Errorf("%w: %s", errUnknownFields, strings.Join(msgs, "; "))
+}
+
+// checkManifestFields checks the fields of every integration of the manifest against the
+// fields of its provider, when they can be found.
+func checkManifestFields(ctx context.Context, manifest *openapi.Manifest) error {
+	for idx := range manifest.Integrations {
+		integ := &manifest.Integrations[idx]
+
+		err := useFieldSource(ctx, integ.Provider)
+		if err != nil {
+			return err
+		}
+
+		err = checkFieldNames(ctx, integ)
+		if err != nil {
+			return fmt.Errorf("integration %s: %w", integ.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// fieldChoice is a field as shown by the field picker.
+type fieldChoice struct {
+	Label   string
+	Details string
+	Name    string
+}
+
+// promptFieldName asks for the name of a field, with a searchable picker if the fields of the
+// object are known.
+func promptFieldName(label string, fields []openapi.FieldMetadata) (string, error) {
+	if len(fields) == 0 {
+		return promptString(label, nonEmpty("Field name"))
+	}
+
+	// Fields may be missing from the metadata, e.g. if they were just created
+	choices := []fieldChoice{{Label: "Another field, type its name"}}
+
+	for _, field := range fields {
+		choice := fieldChoice{Label: field.FieldName, Name: field.FieldName, Details: fieldDetails(field)}
+		if field.DisplayName != "" && field.DisplayName != field.FieldName {
+			choice.Label += " (" + field.DisplayName + ")"
+		}
+
+		choices = append(choices, choice)
+	}
+
+	prompt := promptui.Select{
+		Label:             label,
+		Items:             choices,
+		Size:              NumProvidersShown,
+		StartInSearchMode: true,
+		Searcher: func(input string, index int) bool {
+			return strings.Contains(strings.ToLower(choices[index].Label), strings.ToLower(input))
+		},
+		Templates: &promptui.SelectTemplates{
+			Label:    "{{ . }}",
+			Active:   "& {{ .Label | cyan }}",
+			Inactive: "  {{ .Label | cyan }}",
+			Selected: "Selected {{ .Label | red }}",
+			Details:  "{{ .Details }}",
+		},
+		Stdin:  os.Stdin,
+		Stdout: os.Stdout,
+	}
+
+	idx, _, err := prompt.Run()
+	if err != nil {
+		return "", err
+	}
+
+	if idx == 0 {
+		return promptString(label, nonEmpty("Field name"))
+	}
+
+	return choices[idx].Name, nil
+}
+
+// fieldDetails describes a field in a line or two, e.g. "string, required, read-only".
+func fieldDetails(field openapi.FieldMetadata) string {
+	var traits []string
+
+	if field.ValueType != "" {
+		traits = append(traits, string(field.ValueType))
+	}
+
+	for _, trait := range []struct {
+		set  *bool
+		name string
+	}{
+		{field.IsRequired, "required"},
+		{field.ReadOnly, "read-only"},
+		{field.IsCustom, "custom"},
+	} {
+		if trait.set != nil && *trait.set {
+			traits = append(traits, trait.name)
+		}
+	}
+
+	details := strings.Join(traits, ", ")
+
+	if len(field.Values) > 0 {
+		values := make([]string, 0, len(field.Values))
+		for _, value := range field.Values {
+			values = append(values, value.Value)
+		}
+
+		details += "\nValues: " + strings.Join(values, ", ")
+	}
+
+	return details
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/amp-labs/cli/files"
+	"github.com/amp-labs/cli/internal/scaffold"
+	"github.com/amp-labs/cli/logger"
+	"github.com/amp-labs/cli/openapi"
+	"github.com/spf13/cobra"
+)
+
+var (
+	initTemplate      string            //nolint:gochecknoglobals
+	initTemplateDirs  []string          //nolint:gochecknoglobals
+	initTemplateVars  map[string]string //nolint:gochecknoglobals
+	initListTemplates bool              //nolint:gochecknoglobals
+
+	// initTemplateExclusiveFlags describe parts of the integration, which the template already does.
+	initTemplateExclusiveFlags = []string{ //nolint:gochecknoglobals
+		"answers", "read", "write", "proxy", "subscribe", "watch-schema",
+	}
+)
+
+// addTemplateFlags adds the flags that start the manifest from a template.
+func addTemplateFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&initTemplate, "template", "",
+		"Start from a template, e.g. crm-contacts-sync (see --list-templates)")
+	cmd.Flags().StringSliceVar(&initTemplateDirs, "template-dir", nil,
+		"Directories with more templates, which replace the built-in ones of the same name")
+	cmd.Flags().StringToStringVar(&initTemplateVars, "set", nil,
+		"Variables of the template, e.g. --set object=Contact")
+	cmd.Flags().BoolVar(&initListTemplates, "list-templates", false, "List the templates and exit")
+
+	for _, name := range initTemplateExclusiveFlags {
+		cmd.MarkFlagsMutuallyExclusive("template", name)
+	}
+}
+
+// templateDirs returns the directories to look for templates in, the user's own first.
+func templateDirs() []string {
+	dir, err := scaffold.UserTemplateDir()
+	if err != nil {
+		logger.Debugf("Unable to find the user's templates: %v", err)
+
+		return initTemplateDirs
+	}
+
+	return append([]string{dir}, initTemplateDirs...)
+}
+
+// listTemplates prints the templates that --template can use.
+func listTemplates() error {
+	templates, err := scaffold.Templates(templateDirs()...)
+	if err != nil {
+		return err
+	}
+
+	for _, tmpl := range templates {
+		fmt.Fprintf(os.Stdout, "%s\t%s\n", tmpl.Name, tmpl.Description)
+
+		if tmpl.Dir != "" {
+			fmt.Fprintf(os.Stdout, "\tfrom %s\n", tmpl.Dir)
+		}
+	}
+
+	return nil
+}
+
+// renderTemplate fills in the template given by --template for the provider given by
+// --provider, or else chosen from the catalog. The manifest is checked and formatted before it
+// is returned.
+func renderTemplate(ctx context.Context) ([]byte, error) {
+	tmpl, err := scaffold.FindTemplate(initTemplate, templateDirs()...)
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := lookupOrSelectProvider(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	moduleName := initModule
+	if initProvider == "" && moduleName == "" {
+		moduleName, err = selectModule(provider)
+		if err != nil {
+			return nil, fmt.Errorf("unable to select module: %w", err)
+		}
+	}
+
+	vars := map[string]string{}
+	for key, value := range initTemplateVars {
+		vars[key] = value
+	}
+
+	// The flags that describe read objects are shortcuts for the variables of the same name, for
+	// the templates that have them
+	for key, value := range map[string]string{"destination": initDestination, "schedule": initSchedule} {
+		if _, ok := tmpl.Vars[key]; !ok || value == "" {
+			continue
+		}
+
+		vars[key] = value
+	}
+
+	name := initName
+	if name == "" {
+		name = tmpl.Name
+	}
+
+	data, err := tmpl.Render(name, initDisplayName, provider, moduleName, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := files.ParseManifest(data)
+	if err != nil {
+		return nil, fmt.Errorf("the %s template doesn't make a manifest: %w", tmpl.Name, err)
+	}
+
+	err = files.ValidateManifest(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("the %s template doesn't make a valid manifest: %w", tmpl.Name, err)
+	}
+
+	err = checkManifestFields(ctx, manifest)
+	if err != nil {
+		return nil, fmt.Errorf("the %s template doesn't make a valid manifest: %w", tmpl.Name, err)
+	}
+
+	// Templates are written for people to read, but amp.yaml gets the layout of 'amp fmt'
+	return scaffold.Format(data)
+}
+
+// lookupOrSelectProvider returns the provider given by --provider, or asks for one.
+func lookupOrSelectProvider(ctx context.Context) (*openapi.ProviderInfo, error) {
+	if initProvider != "" {
+		return lookupProvider(ctx, initProvider)
+	}
+
+	provider, err := selectProvider(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to select provider: %w", err)
+	}
+
+	return provider, nil
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/amp-labs/cli/files"
+	"github.com/amp-labs/cli/flags"
+	"github.com/amp-labs/cli/internal/lint"
+	"github.com/amp-labs/cli/logger"
+	"github.com/amp-labs/cli/request"
+	"github.com/spf13/cobra"
+)
+
+var (
+	lintDisabled  []string //nolint:gochecknoglobals
+	lintListRules bool     //nolint:gochecknoglobals
+)
+
+var lintCmd = &cobra.Command{ //nolint:gochecknoglobals
+	Use:   "lint [manifest]",
+	Short: "Check amp.yaml for likely mistakes",
+	Long: `Check a manifest for mistakes that the schema doesn't catch, such as objects declared
+twice, schedules that don't parse or destinations that don't exist.
+
+The manifest is amp.yaml by default, and a directory stands for the amp.yaml in it.
+Destinations are only checked when a project is given.
+
+Each issue names the rule it breaks (see --list-rules). Rules are turned off with
+--disable, or with a comment on the line of a value or alone on the line above it, which
+covers what is nested in the value too:
+
+  schedule: "* * * * *" # amp:lint-ignore invalid-schedule
+  # amp:lint-ignore duplicate-object, missing-schedule
+  - objectName: account
+
+Such a comment above the first key of the file covers the whole file.
+
+The command fails if there are errors, warnings alone don't make it fail.`,
+	Args:   cobra.MaximumNArgs(1),
+	Hidden: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		if lintListRules {
+			for _, rule := range lint.Rules {
+				fmt.Fprintf(os.Stdout, "%s\t%s: %s\n", rule.Id, rule.Severity, rule.Description)
+			}
+
+			return
+		}
+
+		path := "amp.yaml"
+		if len(args) > 0 {
+			path = args[0]
+		}
+
+		path = manifestPath(path)
+
+		issues, err := lintManifest(cmd.Context(), path)
+		if errors.Is(err, files.ErrBadManifest) {
+			fmt.Fprint(os.Stdout, err.Error()+"\n")
+			os.Exit(1)
+		}
+
+		if err != nil {
+			logger.FatalErr("Unable to lint "+path, err)
+		}
+
+		failed := false
+
+		for _, issue := range issues {
+			fmt.Fprintf(os.Stdout, "%s:%s\n", path, issue)
+
+			failed = failed || issue.Rule.Severity == lint.SeverityError
+		}
+
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+// lintManifest checks that the manifest is valid, and then runs the lint rules on it.
+func lintManifest(ctx context.Context, path string) ([]lint.Issue, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest, err := files.ParseManifest(data)
+	if err != nil {
+		return nil, err
+	}
+
+	err = files.ValidateManifest(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	return lint.Lint(data, lint.Options{
+		Disabled:     lintDisabled,
+		Destinations: projectDestinations(ctx),
+	})
+}
+
+// projectDestinations returns the names of the destinations of the project, or nil if there
+// is no project to get them from.
+func projectDestinations(ctx context.Context) []string {
+	project := flags.GetProject()
+	if project == "" {
+		logger.Debug("No project given, destinations are not checked")
+
+		return nil
+	}
+
+	apiKey := flags.GetAPIKey()
+	client := request.NewAPIClient(project, &apiKey)
+
+	dests, err := client.ListDestinations(ctx)
+	if err != nil {
+		logger.Infof("Unable to list destinations, they are not checked: %v", err)
+
+		return nil
+	}
+
+	names := make([]string, 0, len(dests))
+	for _, dest := range dests {
+		names = append(names, dest.Name)
+	}
+
+	return names
+}
+
+func init() {
+	lintCmd.Flags().StringSliceVar(&lintDisabled, "disable", nil, "Rules to turn off (comma-separated)")
+	lintCmd.Flags().BoolVar(&lintListRules, "list-rules", false, "List the rules and exit")
+	rootCmd.AddCommand(lintCmd)
+}
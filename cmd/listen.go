@@ -6,47 +6,253 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
-	"path/filepath"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/amp-labs/cli/files"
+	"github.com/amp-labs/cli/internal/tui"
 	"github.com/amp-labs/cli/internal/webhook"
 	"github.com/amp-labs/cli/logger"
+	"github.com/amp-labs/cli/vars"
 	"github.com/spf13/cobra"
 )
 
 var (
 	ErrFailedToGetTCPAddress = errors.New("failed to get TCP address")
-	forwardURL               string
+	errRespondWithForward    = errors.New("--respond and --forward-to cannot be used together")
+	forwardSpecs             []string
+	forwardTimeout           time.Duration
+	forwardTargets           []*webhook.Target
 	listenAddr               string
+	sessionName              string
+	listenTLS                bool
+	forwardCAFile            string
+	forwardTransport         http.RoundTripper
+	listenManifestFile       string
+	manifestChecker          *webhook.ManifestChecker
+	harFile                  string
+	harRecorder              *webhook.HARRecorder
+	recordEvents             bool
+	maxEvents                int
+	maxEventAge              time.Duration
+	eventStore               *webhook.Store
+	retryPolicy              webhook.RetryPolicy
+	deadLetterSize           int
+	redeliverInterval        time.Duration
+	deadLetters              *webhook.DeadLetterQueue
+	respondSpec              string
+	respondBodyFile          string
+	respondDelay             time.Duration
+	mockResponder            *webhook.MockResponder
+	listenTUI                bool
+	listenUI                 *tui.ListenUI
 	listenCommand            = &cobra.Command{
 		Use:   "listen",
 		Short: "Listen for webhooks locally",
 		Long: `Listen for webhooks locally and forward them to your application.
 This command starts a local webhook server that receives events and forwards them to your application.
-It's designed for local development and testing.`,
+It's designed for local development and testing.
+
+--forward-to can be repeated to fan out each event to several local services.
+A target may be followed by ';'-separated options: a timeout, and routing rules
+that must all match for the target to receive the event:
+
+  timeout=<duration>        how long to wait for this target (e.g. timeout=10s)
+  path=<glob>               request path matches the glob (e.g. path=/hubspot/*)
+  header:<name>=<value>     request header has the value
+  json:<field.path>=<value> JSON body field has the value (e.g. json:provider=hubspot)
+
+The response of the first matching target is returned to the sender.
+
+If a target is unreachable or answers 502, 503 or 504, the request is retried with
+exponential backoff (--retries, --retry-backoff). Events that still could not be
+delivered are kept in a dead-letter buffer and redelivered, in order, once the
+target comes back (--dead-letter-size, --redeliver-interval).
+
+With --respond, nothing is forwarded and the listener answers every webhook itself.
+This is useful to see what Ampersand sends and how delivery retries behave, without
+running your app. --respond takes a comma-separated sequence of status codes, each
+optionally repeated with xN; the last status is used for all further requests.
+
+When run in a terminal, events are shown in an interactive list with a detail pane,
+where they can be filtered, replayed and copied. Use --tui=false, or redirect the
+output, to get plain line output instead.
+
+With --tls, the listener serves HTTPS using a certificate issued by a local CA that
+is generated on first use and cached. Trust the CA file it prints to connect;
+'amp trigger' does so automatically. Use --forward-ca to forward to HTTPS targets
+whose certificate is signed by a CA of your own.
+
+With --manifest, each event is checked against the read object it is for in the
+given amp.yaml, and missing required fields or fields that aren't declared or
+mapped there are flagged as the event arrives.
+
+With --har, every request the listener receives and every copy it forwards is
+written, with its response, to an HTTP Archive file. The file can be shared, opened
+in browser dev tools, or replayed with 'amp trigger --har'.
+
+Several listeners can run at the same time under different --name values; pick
+the one 'amp trigger' sends to with its --session flag.
+
+Examples:
+  amp listen --forward-to http://localhost:4000/webhook
+  amp listen --name billing --forward-to http://localhost:5000/webhook
+  amp listen --forward-to http://localhost:4000/webhook \
+    --forward-to 'http://localhost:5000/ingest;json:provider=hubspot;timeout=30s' \
+    --forward-to 'http://localhost:9000/sink'
+  amp listen --tls --forward-to https://localhost:4443/webhook --forward-ca ./dev-ca.pem
+  amp listen --manifest ./amp.yaml
+  amp listen --har ./repro.har
+  amp listen --respond 200 --respond-body ./ok.json --delay 2s
+  amp listen --respond 500x2,200`,
 		Hidden: true,
 		RunE:   runListen,
 	}
 )
 
 func init() {
-	listenCommand.Flags().StringVar(&forwardURL, "forward-to", "http://localhost:4000/webhook",
-		"URL to forward webhooks to")
 	listenCommand.Flags().StringVar(&listenAddr, "listen", "127.0.0.1:0", "Address to listen on (default is random port)")
+	listenCommand.Flags().StringVar(&sessionName, "name", webhook.DefaultSessionName,
+		"Session name, used by 'amp trigger --session' to find this listener")
+	addListenerFlags(listenCommand)
 	rootCmd.AddCommand(listenCommand)
 }
 
+// addListenerFlags adds the flags that configure the webhook listener, except for its address
+// and session name, whose defaults depend on the command.
+func addListenerFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVar(&forwardSpecs, "forward-to", []string{"http://localhost:4000/webhook"},
+		"URL to forward webhooks to, optionally with ';'-separated routing rules (can be repeated)")
+	cmd.Flags().DurationVar(&forwardTimeout, "forward-timeout", webhook.DefaultTimeout,
+		"Default time to wait for a forward target to respond")
+	cmd.Flags().BoolVar(&listenTLS, "tls", false,
+		"Serve HTTPS with a certificate issued by a locally generated CA")
+	cmd.Flags().StringVar(&forwardCAFile, "forward-ca", "",
+		"PEM file with a CA to trust, in addition to the system's, when forwarding to HTTPS targets")
+	cmd.Flags().StringVar(&listenManifestFile, "manifest", "",
+		"Check received events against the objects declared in this amp.yaml")
+	cmd.Flags().StringVar(&harFile, "har", "",
+		"Write received and forwarded requests and their responses to this HAR file")
+	cmd.Flags().BoolVar(&recordEvents, "record", true,
+		"Record received events so they can be inspected and replayed with 'amp events'")
+	cmd.Flags().IntVar(&maxEvents, "max-events", webhook.DefaultMaxEvents,
+		"Maximum number of recorded events to keep, the oldest are deleted first (0 for no limit)")
+	cmd.Flags().DurationVar(&maxEventAge, "max-event-age", webhook.DefaultMaxEventAge,
+		"Delete recorded events older than this (0 for no limit)")
+	cmd.Flags().IntVar(&retryPolicy.Retries, "retries", 2,
+		"Number of times to retry forwarding to an unavailable target")
+	cmd.Flags().DurationVar(&retryPolicy.Backoff, "retry-backoff", 500*time.Millisecond, //nolint:mnd
+		"Delay before the first retry, doubled for each further retry")
+	cmd.Flags().DurationVar(&retryPolicy.MaxBackoff, "retry-max-backoff", 5*time.Second, //nolint:mnd
+		"Maximum delay between retries")
+	cmd.Flags().IntVar(&deadLetterSize, "dead-letter-size", 100, //nolint:mnd
+		"Maximum number of undelivered events kept for redelivery (0 disables redelivery)")
+	cmd.Flags().DurationVar(&redeliverInterval, "redeliver-interval", 5*time.Second, //nolint:mnd
+		"How often to try redelivering undelivered events")
+	cmd.Flags().StringVar(&respondSpec, "respond", "",
+		"Don't forward, respond with this status code, or sequence of codes (e.g. 500x2,200)")
+	cmd.Flags().StringVar(&respondBodyFile, "respond-body", "",
+		"File containing the body to respond with when using --respond")
+	cmd.Flags().DurationVar(&respondDelay, "delay", 0,
+		"How long to wait before responding when using --respond")
+	cmd.Flags().BoolVar(&listenTUI, "tui", true,
+		"Show an interactive event list when running in a terminal")
+}
+
+// runningListener is a webhook listener started by startListener.
+type runningListener struct {
+	srv      *http.Server
+	session  *webhook.Session
+	registry *webhook.Registry
+}
+
 func runListen(cmd *cobra.Command, args []string) error {
 	// Set up a context that we can cancel
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
+	listener, err := startListener(ctx, cmd)
+	if err != nil {
+		return err
+	}
+
+	defer listener.registry.Unregister(listener.session)
+
+	fmt.Fprint(os.Stdout, "Press Ctrl+C to stop\n")
+
+	return listener.wait(ctx, stop)
+}
+
+// startListener starts serving webhooks as configured by the listener flags of cmd, and
+// registers the session. The caller must unregister it once the listener has stopped.
+func startListener(ctx context.Context, cmd *cobra.Command) (*runningListener, error) {
+	if respondSpec != "" {
+		if cmd.Flags().Changed("forward-to") {
+			return nil, errRespondWithForward
+		}
+
+		responder, err := newMockResponder()
+		if err != nil {
+			return nil, err
+		}
+
+		mockResponder = responder
+	} else {
+		transport, err := newForwardTransport(forwardCAFile)
+		if err != nil {
+			return nil, err
+		}
+
+		forwardTransport = transport
+
+		targets, err := parseForwardTargets(forwardSpecs)
+		if err != nil {
+			return nil, err
+		}
+
+		forwardTargets = targets
+	}
+
+	if listenManifestFile != "" {
+		checker, err := loadManifestChecker(listenManifestFile)
+		if err != nil {
+			return nil, err
+		}
+
+		manifestChecker = checker
+	}
+
+	if harFile != "" {
+		recorder, err := webhook.NewHARRecorder(harFile, vars.Version)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create HAR file: %w", err)
+		}
+
+		harRecorder = recorder
+	}
+
+	if recordEvents {
+		store, err := webhook.DefaultStore()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open event store: %w", err)
+		}
+
+		eventStore = store
+
+		pruneEvents()
+	}
+
+	if deadLetterSize > 0 && mockResponder == nil {
+		deadLetters = webhook.NewDeadLetterQueue(deadLetterSize)
+	}
+
 	// Set up the HTTP server
 	mux := http.NewServeMux()
 	mux.HandleFunc("/", handleWebhook)
@@ -56,22 +262,67 @@ func runListen(cmd *cobra.Command, args []string) error {
 
 	listener, err := lc.Listen(ctx, "tcp", listenAddr)
 	if err != nil {
-		return fmt.Errorf("failed to start listener: %w", err)
+		return nil, fmt.Errorf("failed to start listener: %w", err)
 	}
 
-	// Save the port for the trigger command to use
 	addr, ok := listener.Addr().(*net.TCPAddr)
 	if !ok {
-		return ErrFailedToGetTCPAddress
+		return nil, ErrFailedToGetTCPAddress
 	}
 
 	port := strconv.Itoa(addr.Port)
 
-	// Save the port
+	// Register the session so that the trigger command can find this listener
+	registry, err := webhook.DefaultRegistry()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open listener registry: %w", err)
+	}
+
+	session := &webhook.Session{
+		Name:      sessionName,
+		PID:       os.Getpid(),
+		Address:   net.JoinHostPort(dialableHost(addr.IP), port),
+		ForwardTo: forwardSpecs,
+		StartedAt: time.Now().UTC(),
+	}
+
+	if mockResponder != nil {
+		session.ForwardTo = nil
+	}
+
+	var certs *webhook.Certs
+
+	if listenTLS {
+		certs, err = ensureListenerCerts(ctx, addr.IP)
+		if err != nil {
+			_ = listener.Close()
+
+			return nil, err
+		}
+
+		session.CAFile = certs.CAFile
+	}
 
-	err = saveListenerPort(port)
+	err = registry.Register(ctx, session)
 	if err != nil {
-		logger.FatalErr("failed to save port", err)
+		_ = listener.Close()
+
+		return nil, err
+	}
+
+	// The UI is set up before anything reports to it, it only takes over the terminal in wait
+	listenUI = newListenUI(session.Name + " · " + session.URL())
+
+	if deadLetters != nil {
+		go deadLetters.Run(ctx, redeliverInterval, handleRedelivery)
+	}
+
+	if harRecorder != nil {
+		const harFlushInterval = time.Second
+
+		go harRecorder.Run(ctx, harFlushInterval, func(err error) {
+			listenPrintf("⚠️  Failed to write HAR file: %v", err)
+		})
 	}
 
 	const serverTimeout = 10 * time.Second
@@ -79,25 +330,65 @@ func runListen(cmd *cobra.Command, args []string) error {
 	srv := &http.Server{
 		Handler:           mux,
 		ReadHeaderTimeout: serverTimeout,
+		// Liveness checks from other amp commands connect without a TLS handshake, don't
+		// let the resulting errors clutter the output.
+		ErrorLog: log.New(debugLogWriter{}, "", 0),
 	}
 
 	// Start the server in a goroutine so it doesn't block
+	logger.Info("starting webhook listener")
+
 	go func() {
-		logger.Info("starting webhook listener")
+		var err error
+		if certs != nil {
+			err = srv.ServeTLS(listener, certs.CertFile, certs.KeyFile)
+		} else {
+			err = srv.Serve(listener)
+		}
 
-		err := srv.Serve(listener)
 		if err != nil && !errors.Is(err, http.ErrServerClosed) {
 			logger.FatalErr("webhook listener failed", err)
 		}
 	}()
 
 	// Print the listen address
-	fmt.Fprint(os.Stdout, "🎧 Listening on "+addr.IP.String()+":"+port+"\n")
-	fmt.Fprint(os.Stdout, "ℹ️  Forwarding to: "+forwardURL+"\n")
-	fmt.Fprint(os.Stdout, "Press Ctrl+C to stop\n")
+	fmt.Fprint(os.Stdout, "🎧 Listening on "+addr.IP.String()+":"+port+" (session \""+session.Name+"\")\n")
+
+	if certs != nil {
+		fmt.Fprint(os.Stdout, "🔒 Serving HTTPS, trust this CA to connect: "+certs.CAFile+"\n")
+	}
+
+	for _, target := range forwardTargets {
+		fmt.Fprint(os.Stdout, "ℹ️  Forwarding to: "+target.String()+"\n")
+	}
+
+	if mockResponder != nil {
+		fmt.Fprint(os.Stdout, "🎭 Not forwarding, responding with: "+respondSpec+"\n")
+	}
+
+	if manifestChecker != nil {
+		fmt.Fprint(os.Stdout, "📋 Checking events against: "+listenManifestFile+"\n")
+	}
+
+	if harRecorder != nil {
+		fmt.Fprint(os.Stdout, "📼 Writing HAR to: "+harFile+"\n")
+	}
+
+	if eventStore != nil {
+		fmt.Fprint(os.Stdout, "ℹ️  Recording events to: "+eventStore.Dir()+"\n")
+	}
+
+	return &runningListener{srv: srv, session: session, registry: registry}, nil
+}
+
+// wait shows the interactive UI, if enabled, until the context ends or the user quits
+// it, and then shuts the listener down.
+func (l *runningListener) wait(ctx context.Context, stop context.CancelFunc) error {
+	uiDone := runListenUI(ctx, stop)
 
-	// Wait for interrupt signal
+	// Wait for interrupt signal, or for the user to quit the UI
 	<-ctx.Done()
+	<-uiDone
 	fmt.Fprint(os.Stdout, "\nShutting down webhook listener...\n")
 
 	// Create a deadline to wait for current connections to complete
@@ -107,54 +398,149 @@ func runListen(cmd *cobra.Command, args []string) error {
 
 	defer cancel()
 
-	err = srv.Shutdown(shutdownCtx)
+	err := l.srv.Shutdown(shutdownCtx)
 	if err != nil {
 		logger.FatalErr("shutdown error", err)
 	}
 
-	// Clean up the port file
-	clearListenerPort()
+	// The last entries are written once no more requests are coming in
+	if harRecorder != nil {
+		err = harRecorder.Flush()
+		if err != nil {
+			fmt.Fprintf(os.Stdout, "⚠️  Failed to write HAR file: %v\n", err)
+		}
+	}
+
+	if deadLetters != nil && deadLetters.Len() > 0 {
+		fmt.Fprintf(os.Stdout, "⚠️  %d event(s) were never delivered, use 'amp events replay' to resend them\n",
+			deadLetters.Len())
+	}
 
 	fmt.Fprint(os.Stdout, "Webhook listener stopped\n")
 
 	return nil
 }
 
-const (
-	mkdirPerm = 0o700
-	filePerm  = 0o600
-)
+// loadManifestChecker reads and validates the manifest given by --manifest.
+func loadManifestChecker(path string) (*webhook.ManifestChecker, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
 
-// saveListenerPort saves the listener port to a temporary file.
-func saveListenerPort(port string) error {
-	dir, err := os.UserCacheDir()
+	manifest, err := files.ParseManifest(data)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// Create ampersand directory if it doesn't exist
-	ampDir := filepath.Join(dir, "ampersand")
+	err = files.ValidateManifest(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	return webhook.NewManifestChecker(manifest), nil
+}
 
-	err = os.MkdirAll(ampDir, mkdirPerm)
+// newMockResponder builds the responder for --respond, --respond-body and --delay.
+func newMockResponder() (*webhook.MockResponder, error) {
+	statuses, err := webhook.ParseMockSequence(respondSpec)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	var body []byte
+
+	if respondBodyFile != "" {
+		body, err = os.ReadFile(respondBodyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response body file: %w", err)
+		}
+	}
+
+	return webhook.NewMockResponder(statuses, body, respondDelay), nil
+}
+
+// parseForwardTargets parses the --forward-to flag values.
+func parseForwardTargets(specs []string) ([]*webhook.Target, error) {
+	targets := make([]*webhook.Target, 0, len(specs))
+
+	for _, spec := range specs {
+		target, err := webhook.ParseTarget(spec, forwardTimeout)
+		if err != nil {
+			return nil, err
+		}
+
+		target.Transport = forwardTransport
+		targets = append(targets, target)
+	}
+
+	return targets, nil
+}
+
+// newForwardTransport returns a transport that also trusts the CA in caFile,
+// or nil to use the default transport if caFile is empty.
+func newForwardTransport(caFile string) (http.RoundTripper, error) {
+	if caFile == "" {
+		return nil, nil
 	}
 
-	// Write port to file
-	portFile := filepath.Join(ampDir, "webhook-port")
+	tlsConfig, err := webhook.ClientTLSConfig(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load CA file: %w", err)
+	}
+
+	transport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return &http.Transport{TLSClientConfig: tlsConfig}, nil
+	}
+
+	transport = transport.Clone()
+	transport.TLSClientConfig = tlsConfig
 
-	return os.WriteFile(portFile, []byte(port), filePerm)
+	return transport, nil
 }
 
-// clearListenerPort removes the port file.
-func clearListenerPort() {
-	dir, err := os.UserCacheDir()
+// ensureListenerCerts returns the cached local CA and server certificate, making sure
+// the certificate is also valid for ip if the listener is bound to a specific address.
+func ensureListenerCerts(ctx context.Context, ip net.IP) (*webhook.Certs, error) {
+	dir, err := webhook.DefaultCertsDir()
 	if err != nil {
-		return
+		return nil, err
+	}
+
+	var hosts []string
+	if !ip.IsUnspecified() && !ip.IsLoopback() {
+		hosts = append(hosts, ip.String())
+	}
+
+	certs, err := webhook.EnsureCerts(ctx, dir, hosts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up TLS certificates: %w", err)
 	}
 
-	portFile := filepath.Join(dir, "ampersand", "webhook-port")
-	os.Remove(portFile) // Ignore errors
+	return certs, nil
+}
+
+// debugLogWriter sends the server's own log output to the debug log.
+type debugLogWriter struct{}
+
+func (debugLogWriter) Write(p []byte) (int, error) {
+	logger.Debug(strings.TrimSpace(string(p)))
+
+	return len(p), nil
+}
+
+// dialableHost returns the host that local clients should connect to for a
+// listener bound to ip, which may be a wildcard address.
+func dialableHost(ip net.IP) string {
+	switch {
+	case ip.IsUnspecified() && ip.To4() == nil:
+		return "::1"
+	case ip.IsUnspecified():
+		return "127.0.0.1"
+	default:
+		return ip.String()
+	}
 }
 
 func handleWebhook(writer http.ResponseWriter, req *http.Request) {
@@ -168,7 +554,7 @@ func handleWebhook(writer http.ResponseWriter, req *http.Request) {
 	// Read the request body
 	body, err := io.ReadAll(req.Body)
 	if err != nil {
-		logger.FatalErr("error reading request body", err)
+		listenPrintf("❌ error reading request body: %v", err)
 		http.Error(writer, "Bad request", http.StatusBadRequest)
 
 		return
@@ -176,60 +562,351 @@ func handleWebhook(writer http.ResponseWriter, req *http.Request) {
 
 	req.Body.Close()
 
+	evt := webhook.NewEvent(req, body)
+
+	if harRecorder != nil {
+		capture := &responseCapture{ResponseWriter: writer}
+		writer = capture
+
+		defer recordReceivedHAR(req, evt, capture)
+	}
+
 	// Log the webhook payload
 
-	err = webhook.PrettyPrintJSON(body)
-	if err != nil {
-		logger.FatalErr("error pretty printing JSON", err)
+	if listenUI == nil {
+		err = webhook.PrettyPrintJSON(body)
+		if err != nil {
+			logger.Infof("\n→ Received webhook event (not valid JSON):\n%s", body)
+		}
 	}
 
-	// Forward the request to the application
-	forwardReq, err := http.NewRequestWithContext(req.Context(), http.MethodPost, forwardURL, bytes.NewReader(body))
-	if err != nil {
-		logger.FatalErr("error creating forward request", err)
-		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+	checkManifest(evt)
+
+	if mockResponder != nil {
+		respondWithMock(writer, req, evt)
 
 		return
 	}
 
-	// Copy headers
-	for k, v := range req.Header {
-		if k == "Content-Length" {
-			continue
+	// Forward the request to every application whose routing rules match. Forwarding is
+	// detached from the sender's request so that retries aren't cut short if it hangs up.
+	targets := webhook.Route(forwardTargets, evt)
+	forwarded := time.Now()
+	deliveries := webhook.ForwardAll(context.WithoutCancel(req.Context()), targets, evt, retryPolicy)
+
+	for _, delivery := range deliveries {
+		recordForwardedHAR(evt, delivery, forwarded, "")
+	}
+
+	for _, delivery := range deliveries {
+		evt.Forwards = append(evt.Forwards, delivery.Result)
+	}
+
+	recordEvent(evt)
+
+	// Only hand the event over to the dead-letter buffer once it is fully recorded. The
+	// buffer keeps its own copy, since redelivery updates it from another goroutine.
+	var undelivered []*webhook.Target
+
+	for _, delivery := range deliveries {
+		if !delivery.Delivered() {
+			undelivered = append(undelivered, delivery.Target)
 		}
+	}
 
-		for _, vv := range v {
-			forwardReq.Header.Add(k, vv)
+	if deadLetters != nil {
+		for _, dropped := range deadLetters.Push(evt, undelivered...) {
+			listenPrintf("⚠️  Dead-letter buffer is full, dropped %s for %s", dropped.Event.Id, dropped.Target.URL)
 		}
 	}
 
-	// Forward the request
-	const clientTimeout = 5 * time.Second
+	for _, delivery := range deliveries {
+		listenPrintf("%s", deliverySummary(evt, delivery))
+	}
 
-	client := &http.Client{Timeout: clientTimeout}
+	showEvent(evt)
 
-	resp, err := client.Do(forwardReq)
-	if err != nil {
-		logger.FatalErr("error forwarding request to "+forwardURL, err)
-		// Still return 200 to the original sender
+	if len(deliveries) == 0 {
+		listenPrintf("➖ %s → no forward target matched this event", evt.Id)
 		writer.WriteHeader(http.StatusOK)
 
 		return
 	}
 
-	defer resp.Body.Close()
+	// The first matching target is the primary one, its response is sent back to the original sender
+	primary := deliveries[0]
+	if primary.Response == nil {
+		// Still return 200 to the original sender, the event is recorded and can be redelivered
+		writer.WriteHeader(http.StatusOK)
+
+		return
+	}
 
 	// Copy the response from the application back to the original sender
-	for k, v := range resp.Header {
+	for k, v := range primary.Response.Header {
 		for _, vv := range v {
 			writer.Header().Add(k, vv)
 		}
 	}
 
-	writer.WriteHeader(resp.StatusCode)
+	writer.WriteHeader(primary.Response.StatusCode)
+
+	_, err = writer.Write(primary.Response.Body)
+	if err != nil {
+		listenPrintf("⚠️  error copying response back to the sender: %v", err)
+	}
+}
+
+// checkManifest compares the event with the manifest given by --manifest, if any,
+// and reports any mismatch.
+func checkManifest(evt *webhook.Event) {
+	if manifestChecker == nil {
+		return
+	}
+
+	evt.ManifestFindings = manifestChecker.Check(evt.Body)
+
+	if len(evt.ManifestFindings) == 0 {
+		listenPrintf("📋 %s matches %s", evt.Id, listenManifestFile)
+
+		return
+	}
+
+	for _, finding := range evt.ManifestFindings {
+		listenPrintf("⚠️  %s doesn't match %s: %s", evt.Id, listenManifestFile, finding)
+	}
+}
+
+// responseCapture keeps a copy of the response sent back to the webhook sender.
+type responseCapture struct {
+	http.ResponseWriter
+
+	status int
+	body   bytes.Buffer
+}
+
+func (c *responseCapture) WriteHeader(status int) {
+	c.status = status
+	c.ResponseWriter.WriteHeader(status)
+}
+
+func (c *responseCapture) Write(data []byte) (int, error) {
+	c.body.Write(data)
+
+	return c.ResponseWriter.Write(data)
+}
+
+// recordReceivedHAR adds a received request and the listener's response to the HAR file.
+func recordReceivedHAR(req *http.Request, evt *webhook.Event, capture *responseCapture) {
+	scheme := "http"
+	if req.TLS != nil {
+		scheme = "https"
+	}
+
+	status := capture.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	entry := webhook.NewHAREntry(evt.ReceivedAt, time.Since(evt.ReceivedAt),
+		webhook.NewHARRequest(req.Method, scheme+"://"+req.Host+req.URL.RequestURI(), evt.Headers, evt.Body),
+		&webhook.Response{StatusCode: status, Header: capture.Header(), Body: capture.body.Bytes()}, nil)
+	entry.Role = webhook.HARReceived
+	entry.EventId = evt.Id
+
+	harRecorder.Add(entry)
+}
+
+// recordForwardedHAR adds a forwarded copy of an event and the target's response to the HAR file.
+func recordForwardedHAR(evt *webhook.Event, delivery *webhook.Delivery, started time.Time, comment string) {
+	if harRecorder == nil {
+		return
+	}
+
+	entry := webhook.NewHAREntry(started, delivery.Result.Latency,
+		webhook.NewHARRequest(http.MethodPost, delivery.Target.URL, evt.Headers, evt.Body),
+		delivery.Response, delivery.Err)
+	entry.Role = webhook.HARForwarded
+	entry.EventId = evt.Id
+
+	if comment != "" {
+		entry.Comment = strings.TrimPrefix(entry.Comment+"; "+comment, "; ")
+	}
+
+	harRecorder.Add(entry)
+}
+
+// respondWithMock answers the webhook with the next scripted response instead of forwarding it.
+func respondWithMock(writer http.ResponseWriter, req *http.Request, evt *webhook.Event) {
+	resp := mockResponder.Next()
+	evt.MockStatus = resp.StatusCode
+
+	recordEvent(evt)
+
+	line := fmt.Sprintf("🎭 %s → responding %s", evt.Id, statusLine(resp.StatusCode))
+	if resp.Steps > 1 {
+		line += fmt.Sprintf(" (step %d of %d)", resp.Step, resp.Steps)
+	}
+
+	if mockResponder.Delay > 0 {
+		line += " after " + mockResponder.Delay.String()
+	}
+
+	listenPrintf("%s", line)
+	showEvent(evt)
+
+	mockResponder.Write(writer, req, resp)
+}
+
+// handleRedelivery reports an event that reached its target from the dead-letter
+// buffer, and updates the recorded event with the new outcome.
+func handleRedelivery(item webhook.DeadLetter, delivery *webhook.Delivery) {
+	recordForwardedHAR(item.Event, delivery, time.Now().Add(-delivery.Result.Latency), "redelivered")
+
+	listenPrintf("%s", deliverySummary(item.Event, delivery))
+
+	if eventStore != nil {
+		err := eventStore.Save(item.Event)
+		if err != nil {
+			listenPrintf("⚠️  Unable to record redelivery of event %s: %v", item.Event.Id, err)
+		}
+	}
+
+	showEvent(item.Event)
+}
+
+// deliverySummary renders one line per event and target, e.g.
+// "✅ evt_... → http://localhost:4000/webhook 200 OK (12ms)".
+func deliverySummary(evt *webhook.Event, delivery *webhook.Delivery) string {
+	icon := "✅"
+
+	switch {
+	case !delivery.Delivered():
+		icon = "❌"
+	case delivery.Response.StatusCode >= http.StatusBadRequest:
+		icon = "⚠️ "
+	}
+
+	line := icon + " " + evt.Id + " → " + delivery.Target.URL + " " + forwardSummary(delivery.Result)
+
+	switch {
+	case delivery.Result.Redelivered:
+		line += " (redelivered)"
+	case !delivery.Delivered() && deadLetters != nil:
+		line += fmt.Sprintf(" — queued for redelivery (%d pending)", deadLetters.Len())
+	}
+
+	return line
+}
+
+// recordEvent saves the event to the event store, unless recording is turned off.
+// Failing to record never interrupts the delivery itself.
+func recordEvent(evt *webhook.Event) {
+	if eventStore == nil {
+		return
+	}
+
+	err := eventStore.Save(evt)
+	if err != nil {
+		listenPrintf("⚠️  Unable to record event %s: %v", evt.Id, err)
+
+		return
+	}
+
+	if listenUI == nil {
+		fmt.Fprint(os.Stdout, "ℹ️  Recorded as "+evt.Id+"\n")
+	}
+
+	pruneEvents()
+}
+
+// pruneEvents keeps the event store within --max-events and --max-event-age.
+func pruneEvents() {
+	_, err := eventStore.Prune(maxEvents, maxEventAge)
+	if err != nil {
+		listenPrintf("⚠️  Unable to delete old events: %v", err)
+	}
+}
+
+// newListenUI returns the interactive event list, or nil if it is disabled or stdin and
+// stdout aren't both terminals.
+func newListenUI(addr string) *tui.ListenUI {
+	if !listenTUI || !isTerminal(os.Stdin.Fd()) || !isTerminal(os.Stdout.Fd()) {
+		return nil
+	}
+
+	title := "amp listen · " + addr
+	if mockResponder != nil {
+		title += " · responding " + respondSpec
+	} else {
+		for _, target := range forwardTargets {
+			title += " → " + target.URL
+		}
+	}
+
+	ui := tui.New(os.Stdin, os.Stdout, title)
+	ui.OnReplay = replayFromUI
+
+	return ui
+}
+
+// runListenUI takes over the terminal with the interactive event list, if there is one.
+// The returned channel is closed once the terminal has been restored; quitting the UI
+// stops the listener.
+func runListenUI(ctx context.Context, stop context.CancelFunc) <-chan struct{} {
+	done := make(chan struct{})
+
+	if listenUI == nil {
+		close(done)
+
+		return done
+	}
+
+	go func() {
+		defer close(done)
+		defer stop()
+
+		err := listenUI.Run(ctx)
+		if err != nil {
+			logger.Infof("⚠️  Unable to start the interactive UI: %v", err)
+		}
+	}()
+
+	return done
+}
 
-	_, err = io.Copy(writer, resp.Body)
+// replayFromUI replays an event to the targets it was originally forwarded to.
+func replayFromUI(evt *webhook.Event) string {
+	targets, err := replayTargets(nil, evt, forwardTransport)
 	if err != nil {
-		logger.FatalErr("error copying response", err)
+		return err.Error()
+	}
+
+	parts := make([]string, 0, len(targets))
+	for _, delivery := range webhook.ForwardAll(context.Background(), targets, evt, webhook.RetryPolicy{}) {
+		parts = append(parts, delivery.Target.URL+" "+forwardSummary(delivery.Result))
+	}
+
+	return "replayed " + evt.Id + ": " + strings.Join(parts, ", ")
+}
+
+// showEvent adds the event to the interactive list, or refreshes it there.
+func showEvent(evt *webhook.Event) {
+	if listenUI != nil {
+		listenUI.Upsert(evt)
+	}
+}
+
+// listenPrintf reports a line about the listener's progress, either on stdout
+// or, while the interactive UI is running, in its footer.
+func listenPrintf(format string, args ...any) {
+	line := fmt.Sprintf(format, args...)
+
+	if listenUI != nil {
+		listenUI.Status(line)
+
+		return
 	}
+
+	fmt.Fprint(os.Stdout, line+"\n")
 }
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/amp-labs/cli/files"
+	"github.com/amp-labs/cli/internal/schedule"
+	"github.com/spf13/cobra"
+)
+
+var errInvalidRuns = errors.New("--runs must be at least 1")
+
+var (
+	scheduleManifestFile string //nolint:gochecknoglobals
+	scheduleRuns         int    //nolint:gochecknoglobals
+	scheduleTimeZone     string //nolint:gochecknoglobals
+
+	scheduleCmd = &cobra.Command{ //nolint:gochecknoglobals
+		Use:    "schedule",
+		Short:  "Inspect the schedules of amp.yaml",
+		Hidden: true,
+	}
+
+	schedulePreviewCmd = &cobra.Command{ //nolint:gochecknoglobals
+		Use:   "preview [integration]",
+		Short: "Print the next runs of the scheduled reads of an integration",
+		Long: `Print the next runs of the scheduled reads of an integration, to check that the
+schedules say what was meant.
+
+Schedules run in UTC, and the times are printed in the time zone given by --timezone,
+e.g. America/New_York (default: the local one). The integration can be left out when
+the manifest has only one.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: runSchedulePreview,
+	}
+)
+
+func runSchedulePreview(_ *cobra.Command, args []string) error {
+	if scheduleRuns < 1 {
+		return errInvalidRuns
+	}
+
+	loc, err := time.LoadLocation(scheduleTimeZone)
+	if err != nil {
+		return fmt.Errorf("invalid --timezone: %w", err)
+	}
+
+	data, err := os.ReadFile(manifestPath(scheduleManifestFile))
+	if err != nil {
+		return err
+	}
+
+	manifest, err := files.ParseManifest(data)
+	if err != nil {
+		return err
+	}
+
+	integ, err := findIntegration(manifest, args)
+	if err != nil {
+		return err
+	}
+
+	if integ.Read == nil || integ.Read.Objects == nil || len(*integ.Read.Objects) == 0 {
+		fmt.Fprintf(os.Stdout, "%s doesn't read any objects\n", integ.Name)
+
+		return nil
+	}
+
+	now := time.Now()
+
+	for idx, obj := range *integ.Read.Objects {
+		if idx > 0 {
+			fmt.Fprintln(os.Stdout)
+		}
+
+		if obj.Schedule == "" {
+			fmt.Fprintf(os.Stdout, "%s has no schedule\n", obj.ObjectName)
+
+			continue
+		}
+
+		sched, err := schedule.Parse(obj.Schedule)
+		if err != nil {
+			fmt.Fprintf(os.Stdout, "%s: %v\n", obj.ObjectName, err)
+
+			continue
+		}
+
+		fmt.Fprintf(os.Stdout, "%s (%s):\n", obj.ObjectName, obj.Schedule)
+
+		for _, run := range sched.Next(now, scheduleRuns) {
+			fmt.Fprintf(os.Stdout, "  %s\n", run.In(loc).Format("Mon 2006-01-02 15:04 MST"))
+		}
+
+		if aggressive, interval := sched.TooAggressive(schedule.MinReadInterval); aggressive {
+			fmt.Fprintf(os.Stdout, "  Warning: runs every %s at times, more often than every %s\n",
+				schedule.FormatInterval(interval), schedule.FormatInterval(schedule.MinReadInterval))
+		}
+	}
+
+	return nil
+}
+
+func init() {
+	schedulePreviewCmd.Flags().StringVar(&scheduleManifestFile, "manifest", "amp.yaml", "Path to the manifest")
+	schedulePreviewCmd.Flags().IntVarP(&scheduleRuns, "runs", "n", 5, "Number of runs to print per object") //nolint:mnd
+	schedulePreviewCmd.Flags().StringVar(&scheduleTimeZone, "timezone", "Local", "Time zone of the printed times")
+
+	scheduleCmd.AddCommand(schedulePreviewCmd)
+	rootCmd.AddCommand(scheduleCmd)
+}
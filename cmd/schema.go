@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/amp-labs/cli/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+var (
+	schemaOutput string //nolint:gochecknoglobals
+
+	schemaCmd = &cobra.Command{ //nolint:gochecknoglobals
+		Use:    "schema",
+		Short:  "Work with the JSON Schema of amp.yaml",
+		Hidden: true,
+	}
+
+	schemaExportCmd = &cobra.Command{ //nolint:gochecknoglobals
+		Use:   "export",
+		Short: "Print the JSON Schema of amp.yaml",
+		Long: `Print the JSON Schema of amp.yaml, for editors to complete and check manifests.
+
+'amp validate' checks manifests against the same schema. With the YAML extension of
+VS Code for example, point the yaml.schemas setting at the exported file:
+
+  amp schema export -o amp.schema.json
+
+  "yaml.schemas": {"./amp.schema.json": "amp.yaml"}`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if schemaOutput == "" {
+				_, err := os.Stdout.Write(schema.JSON())
+
+				return err
+			}
+
+			err := os.WriteFile(schemaOutput, schema.JSON(), YamlFileMode) //nolint:gosec
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(os.Stdout, "Schema written to %s\n", schemaOutput)
+
+			return nil
+		},
+	}
+)
+
+func init() {
+	schemaExportCmd.Flags().StringVarP(&schemaOutput, "output", "o", "", "File to write the schema to (default: stdout)")
+
+	schemaCmd.AddCommand(schemaExportCmd)
+	rootCmd.AddCommand(schemaCmd)
+}
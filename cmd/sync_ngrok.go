@@ -2,17 +2,18 @@ package cmd
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"net"
-	"net/http"
 	"net/url"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/amp-labs/cli/flags"
+	"github.com/amp-labs/cli/internal/tunnel"
 	"github.com/amp-labs/cli/logger"
 	"github.com/amp-labs/cli/request"
 	"github.com/manifoldco/promptui"
@@ -30,16 +31,12 @@ const (
 
 var (
 	// Static errors for linter compliance.
-	errProtocolEmpty         = errors.New("protocol cannot be empty")
-	errInvalidProtocol       = errors.New("invalid protocol: must be 'http' or 'https'")
-	errNgrokServerEmpty      = errors.New("ngrok server cannot be empty")
-	errNoTunnelsFound        = errors.New("no ngrok tunnels found")
-	errNgrokAddressEmpty     = errors.New("ngrok server address cannot be empty")
-	errNoDestinations        = errors.New("no valid destinations provided")
-	errNotWebhook            = errors.New("destination is not a webhook")
-	errInvalidDestination    = errors.New("invalid destination")
-	errNgrokUnexpectedStatus = errors.New("ngrok API returned unexpected status code")
-	errDeadlineExceeded      = errors.New("timeout waiting for ngrok")
+	errProtocolEmpty      = errors.New("protocol cannot be empty")
+	errNgrokServerEmpty   = errors.New("ngrok server cannot be empty")
+	errNoDestinations     = errors.New("no valid destinations provided")
+	errNotWebhook         = errors.New("destination is not a webhook")
+	errInvalidDestination = errors.New("invalid destination")
+	errDeadlineExceeded   = errors.New("timeout waiting for tunnel agent")
 
 	// destinations stores the list of destination names or IDs provided via command-line flags.
 	// These can be either destination names (human-readable) or UUIDs (unique identifiers).
@@ -49,6 +46,13 @@ var (
 	// When true, the command will automatically proceed with all updates without asking for confirmation.
 	skipConfirm bool //nolint:gochecknoglobals
 
+	// restoreOnExit keeps the command running after the sync, and puts the destinations
+	// back to their original URLs when it is interrupted.
+	restoreOnExit bool //nolint:gochecknoglobals
+
+	// restoreOnly skips the sync and puts destinations changed by earlier syncs back.
+	restoreOnly bool //nolint:gochecknoglobals
+
 	// ngrokServer specifies the ngrok server URL for API calls.
 	// Defaults to localhost:4040 but can be configured via command-line flag.
 	ngrokServer string //nolint:gochecknoglobals
@@ -60,9 +64,18 @@ var (
 	// ngrokCommand defines the Cobra command structure for the ngrok subcommand.
 	// This command is hidden from the main help output as it's primarily for development use.
 	syncNgrokCommand = &cobra.Command{ //nolint:gochecknoglobals
-		Use:    "sync-ngrok",
-		Short:  "Sync Ampersand webhook destinations with ngrok tunnels",
-		Long:   "Configure Ampersand destinations to use ngrok tunnels for local development.",
+		Use:   "sync-ngrok",
+		Short: "Sync Ampersand webhook destinations with ngrok tunnels",
+		Long: `Configure Ampersand destinations to use ngrok tunnels for local development.
+
+The URL each destination had before its first sync is remembered, so that shared
+destinations can be put back when you are done:
+  amp sync-ngrok -D my-destination --restore-on-exit   # restores on Ctrl+C
+  amp sync-ngrok --restore                             # restores every synced destination
+
+Free ngrok URLs change on every restart. With --watch, the command keeps running and
+updates the destinations whenever the URL changes, without asking again:
+  amp sync-ngrok -D my-destination --watch --restore-on-exit`,
 		Hidden: true, // Hidden because it's a development-only feature
 		RunE:   runSyncNgrok,
 	}
@@ -108,21 +121,6 @@ func (d Destination) NameOrId() string {
 	return d.Id
 }
 
-// ngrokTunnel represents a single tunnel from ngrok's API response.
-// Contains the public URL that ngrok has assigned to forward traffic to the local service.
-type ngrokTunnel struct {
-	// PublicURL is the externally accessible URL that ngrok provides
-	// (e.g., "https://abc123.ngrok.io")
-	PublicURL string `json:"public_url"` //nolint:tagliatelle
-}
-
-// ngrokResponse represents the JSON response from ngrok's local API endpoint.
-// The ngrok agent exposes an API at localhost:4040 that provides information about active tunnels.
-type ngrokResponse struct {
-	// Tunnels is an array of all currently active ngrok tunnels
-	Tunnels []ngrokTunnel `json:"tunnels"`
-}
-
 // init initializes the ngrok command by setting up command-line flags and
 // registering the command with the root command. This function is called
 // automatically when the package is imported.
@@ -138,6 +136,12 @@ func init() {
 		"yes", "y", false,
 		"Skip confirmation prompts")
 
+	// Set up the restore flags so shared destinations don't keep pointing at a dead tunnel
+	addRestoreFlags(syncNgrokCommand)
+
+	// Set up the watch flags for tunnels whose URL changes on every restart
+	addWatchFlags(syncNgrokCommand)
+
 	// Set up the ngrok server flag for configurable ngrok API endpoint
 	syncNgrokCommand.Flags().StringVarP(&ngrokServer,
 		"ngrok-server", "s", "localhost:4040",
@@ -152,48 +156,43 @@ func init() {
 	rootCmd.AddCommand(syncNgrokCommand)
 }
 
-// validateProtocol validates that the ngrok protocol flag is set to a valid value.
-// Returns an error if the protocol is not "http" or "https".
-func validateProtocol() error {
+// newNgrokProvider validates the ngrok flags and returns the provider for the ngrok agent API.
+func newNgrokProvider() (tunnel.Provider, error) {
 	if ngrokProtocol == "" {
-		return errProtocolEmpty
+		return nil, errProtocolEmpty
 	}
 
-	if ngrokProtocol != "http" && ngrokProtocol != "https" {
-		return fmt.Errorf("%w: '%s'", errInvalidProtocol, ngrokProtocol)
+	if ngrokServer == "" {
+		return nil, errNgrokServerEmpty
 	}
 
-	return nil
+	return tunnel.New(tunnel.Ngrok, tunnel.Config{NgrokAddress: ngrokServer, NgrokProtocol: ngrokProtocol})
 }
 
-// getPublicNgrokURLWithRetry retrieves the public URL of an active ngrok tunnel with retry logic.
-// It handles the case where ngrok is running but still initializing (up to 10 seconds).
+// getPublicURLWithRetry retrieves an active tunnel with retry logic.
+// It handles the case where the tunnel agent is running but still initializing (up to 10 seconds).
 // Uses exponential backoff to reduce API load during initialization.
-// Returns the selected tunnel's public URL or an error if retries are exhausted.
-func getPublicNgrokURLWithRetry(ctx context.Context) (string, error) {
-	if ngrokServer == "" {
-		return "", errNgrokServerEmpty
-	}
-
+// Returns the selected tunnel or an error if retries are exhausted.
+func getPublicURLWithRetry(ctx context.Context, provider tunnel.Provider) (tunnel.Tunnel, error) {
 	deadline := time.Now().Add(maxRetryDuration)
 	delay := initialDelay
 
 	for {
-		// Attempt to get the ngrok URL
-		publicURL, err := getPublicNgrokURL(ctx)
+		// Attempt to get the tunnel
+		chosen, err := getPublicURL(ctx, provider)
 		if err == nil {
-			return publicURL, nil
+			return chosen, nil
 		}
 		// Check if we've exceeded the retry deadline
 		if time.Now().After(deadline) {
-			return "", fmt.Errorf("failed to get ngrok URL after %v: %w",
-				maxRetryDuration.String(), err)
+			return tunnel.Tunnel{}, fmt.Errorf("failed to get %s URL after %v: %w",
+				provider.Name(), maxRetryDuration.String(), err)
 		}
 
 		// Check if context was canceled
 		select {
 		case <-ctx.Done():
-			return "", ctx.Err()
+			return tunnel.Tunnel{}, ctx.Err()
 		case <-time.After(delay):
 		}
 
@@ -205,86 +204,38 @@ func getPublicNgrokURLWithRetry(ctx context.Context) (string, error) {
 	}
 }
 
-// getPublicNgrokURL retrieves the public URL of an active ngrok tunnel by querying
-// the ngrok local API endpoint. If multiple tunnels exist, it prompts the user to choose one.
-// Returns the selected tunnel's public URL or an error if ngrok is not accessible.
-func getPublicNgrokURL(ctx context.Context) (string, error) {
-	// Create HTTP request to ngrok's API endpoint
-	// The ngrok agent exposes a REST API, typically on port 4040 by default
-	apiURL := url.URL{
-		Scheme: ngrokProtocol,
-		Host:   ngrokServer,
-		Path:   "/api/tunnels",
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL.String(), nil)
-	if err != nil {
-		// Wrap the error with context about what operation failed
-		return "", fmt.Errorf("failed to create ngrok API request: %w", err)
-	}
-
-	// Execute the HTTP request to get tunnel information
-	resp, err := http.DefaultClient.Do(req)
+// getPublicURL retrieves an active tunnel by querying the provider's local agent.
+// If multiple tunnels exist, it prompts the user to choose one.
+// Returns the selected tunnel or an error if the agent is not accessible.
+func getPublicURL(ctx context.Context, provider tunnel.Provider) (tunnel.Tunnel, error) {
+	tunnels, err := provider.Tunnels(ctx)
 	if err != nil {
-		// This error typically means ngrok is not running or not accessible
-		return "", fmt.Errorf("failed to connect to ngrok API: %w", err)
-	}
-
-	// Ensure response body is properly closed to prevent resource leaks
-	defer func() {
-		closeErr := resp.Body.Close()
-		if closeErr != nil {
-			_, _ = fmt.Fprintf(os.Stderr, "error closing response body: %v\n", closeErr)
-		}
-	}()
-
-	// Check if the ngrok API responded successfully
-	if resp.StatusCode != http.StatusOK {
-		// Non-200 status codes indicate ngrok API issues
-		return "", fmt.Errorf("%w %d", errNgrokUnexpectedStatus, resp.StatusCode)
-	}
-
-	// Parse the JSON response containing tunnel information
-	var ngrokResp ngrokResponse
-
-	decoder := json.NewDecoder(resp.Body)
-
-	err = decoder.Decode(&ngrokResp)
-	if err != nil {
-		// JSON parsing errors indicate malformed response from ngrok
-		return "", fmt.Errorf("failed to parse ngrok response: %w", err)
+		return tunnel.Tunnel{}, fmt.Errorf("%s: %w", provider.Name(), err)
 	}
 
 	// Handle tunnel selection (single tunnel vs. multiple tunnels)
-	return chooseNgrokTunnel(&ngrokResp)
+	return chooseTunnel(provider, tunnels)
 }
 
-// chooseNgrokTunnel handles tunnel selection when multiple ngrok tunnels are active.
+// chooseTunnel handles tunnel selection when multiple tunnels are active.
 // If only one tunnel exists, it returns that tunnel's URL automatically.
 // If multiple tunnels exist, it presents an interactive prompt for the user to choose.
-// Returns the selected tunnel's public URL or an error if selection fails.
-func chooseNgrokTunnel(ngrokResp *ngrokResponse) (string, error) {
+// Returns the selected tunnel or an error if selection fails.
+func chooseTunnel(provider tunnel.Provider, tunnels []tunnel.Tunnel) (tunnel.Tunnel, error) {
 	// Validate that at least one tunnel is available
-	if len(ngrokResp.Tunnels) == 0 {
-		// This means ngrok is running but no tunnels are active
-		return "", errNoTunnelsFound
+	if len(tunnels) == 0 {
+		return tunnel.Tunnel{}, tunnel.ErrNoTunnels
 	}
 
 	// If only one tunnel exists, use it automatically (no need to prompt)
-	if len(ngrokResp.Tunnels) == 1 {
-		return ngrokResp.Tunnels[0].PublicURL, nil
-	}
-
-	// Extract URLs from tunnels for user selection
-	urls := make([]string, len(ngrokResp.Tunnels))
-	for i, tunnel := range ngrokResp.Tunnels {
-		urls[i] = tunnel.PublicURL
+	if len(tunnels) == 1 {
+		return tunnels[0], nil
 	}
 
 	// Present interactive selection prompt for multiple tunnels
 	prompt := promptui.Select{
-		Label:  "Choose ngrok tunnel",
-		Items:  urls,
+		Label:  "Choose " + provider.Name() + " tunnel",
+		Items:  tunnel.PublicURLs(tunnels),
 		Stdin:  os.Stdin,
 		Stdout: os.Stdout,
 	}
@@ -293,29 +244,29 @@ func chooseNgrokTunnel(ngrokResp *ngrokResponse) (string, error) {
 	idx, _, err := prompt.Run()
 	if err != nil {
 		// User canceled selection or prompt failed
-		return "", fmt.Errorf("failed to select tunnel: %w", err)
+		return tunnel.Tunnel{}, fmt.Errorf("failed to select tunnel: %w", err)
 	}
 
-	return urls[idx], nil
+	return tunnels[idx], nil
 }
 
-// waitForNgrok waits for the ngrok service to become available on the configured server.
-// It performs an initial connectivity check, and if ngrok is not immediately available,
+// waitForAgent waits for the provider's local agent to become available.
+// It performs an initial connectivity check, and if the agent is not immediately available,
 // it retries with a timeout and visual progress indicator (dots).
-// Returns nil when ngrok is accessible, or an error if the timeout is exceeded.
-func waitForNgrok(ctx context.Context) error {
-	// Use configured ngrok server address
-	address := ngrokServer
+// Returns nil when the agent is accessible, or an error if the timeout is exceeded.
+func waitForAgent(ctx context.Context, provider tunnel.Provider) error {
+	// Providers without a local agent have nothing to wait for
+	address := provider.AgentAddress()
 	if address == "" {
-		return errNgrokAddressEmpty
+		return nil
 	}
 
-	// Perform initial connectivity check to see if ngrok is already running
+	// Perform initial connectivity check to see if the agent is already running
 	dialer := net.Dialer{Timeout: connectTimeout}
 
 	conn, err := dialer.DialContext(ctx, "tcp", address)
 	if err == nil {
-		// ngrok is already available, close connection and return immediately
+		// The agent is already available, close connection and return immediately
 		_ = conn.Close()
 
 		return nil
@@ -352,10 +303,10 @@ func waitForNgrok(ctx context.Context) error {
 					errDeadlineExceeded, address, maxWaitDuration)
 			}
 
-			// Attempt to connect to ngrok API endpoint
+			// Attempt to connect to the agent's API endpoint
 			conn, err = dialer.DialContext(ctx, "tcp", address)
 			if err == nil {
-				// Success! ngrok is now available
+				// Success! The agent is now available
 				_ = conn.Close()
 
 				return nil
@@ -373,32 +324,62 @@ func waitForNgrok(ctx context.Context) error {
 // It orchestrates the entire process: setup, ngrok tunnel discovery, and destination updates.
 // This function is called by the Cobra framework when the ngrok command is invoked.
 func runSyncNgrok(cmd *cobra.Command, _ []string) error {
-	// Validate protocol flag before proceeding
-	err := validateProtocol()
+	// Restoring doesn't involve the tunnel at all
+	if restoreOnly {
+		return runRestore(cmd.Context())
+	}
+
+	// Validate the ngrok flags before proceeding
+	provider, err := newNgrokProvider()
 	if err != nil {
 		return err
 	}
 
+	return syncTunnel(cmd.Context(), provider)
+}
+
+// syncTunnel points the destinations given by --destination at the provider's public URL.
+// It is shared by 'amp sync-ngrok' and 'amp tunnel sync'.
+func syncTunnel(ctx context.Context, provider tunnel.Provider) error {
+	// With --restore-on-exit, an interrupt at any point restores what was changed so far,
+	// and with --watch it is how the session ends
+	if restoreOnExit || watchTunnel {
+		var stop context.CancelFunc
+
+		ctx, stop = signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+		defer stop()
+	}
+
 	// Phase 1: Initialize API client and resolve destination identifiers
-	client, dests, err := setupNgrokExecution(cmd.Context())
+	client, dests, err := setupNgrokExecution(ctx)
 	if err != nil {
 		// Setup errors are passed through directly as they already have context
 		return err
 	}
 
-	// Phase 2: Wait for ngrok and get the public tunnel URL
-	publicURL, err := getNgrokTunnelURL(cmd.Context())
+	// Phase 2: Wait for the tunnel agent and get the public tunnel URL
+	chosen, err := getTunnel(ctx, provider)
 	if err != nil {
-		// Ngrok-related errors are passed through with their original context
+		// Tunnel-related errors are passed through with their original context
 		return err
 	}
 
-	// Phase 3: Update all specified destinations with the ngrok URL
-	stats := updateDestinations(cmd.Context(), client, dests, publicURL)
+	// Phase 3: Update all specified destinations with the tunnel URL
+	stats := updateDestinations(ctx, client, dests, chosen.PublicURL, true)
 
 	// Phase 4: Report the results to the user
 	logDestinationStats(stats, len(dests))
 
+	// Phase 5: With --watch, follow the tunnel until the session ends
+	if watchTunnel {
+		watchTunnelURL(ctx, client, provider, chosen, stats.synced)
+	}
+
+	// Phase 6: With --restore-on-exit, hold the destinations until the session ends
+	if restoreOnExit {
+		return restoreOnInterrupt(ctx, client, dests)
+	}
+
 	return nil
 }
 
@@ -428,33 +409,37 @@ func setupNgrokExecution(ctx context.Context) (*request.APIClient, []Destination
 	return client, dests, nil
 }
 
-// getNgrokTunnelURL manages the process of waiting for ngrok to start and retrieving
+// getTunnel manages the process of waiting for the tunnel agent to start and retrieving
 // the public tunnel URL. It provides user feedback during the wait process and
-// handles the tunnel URL selection if multiple tunnels are available.
-// Returns the selected ngrok public URL or an error if the process fails.
-func getNgrokTunnelURL(ctx context.Context) (string, error) {
-	// Step 1: Wait for ngrok service to become available
-	logger.Info("waiting for ngrok to start...")
+// handles the tunnel selection if multiple tunnels are available.
+// Returns the selected tunnel or an error if the process fails.
+func getTunnel(ctx context.Context, provider tunnel.Provider) (tunnel.Tunnel, error) {
+	name := provider.Name()
 
-	err := waitForNgrok(ctx)
-	if err != nil {
-		// Provide helpful context about ngrok not being available
-		return "", fmt.Errorf("ngrok is not running: %w", err)
-	}
+	// Step 1: Wait for the agent to become available
+	if provider.AgentAddress() != "" {
+		logger.Infof("waiting for %s to start...", name)
+
+		err := waitForAgent(ctx, provider)
+		if err != nil {
+			// Provide helpful context about the agent not being available
+			return tunnel.Tunnel{}, fmt.Errorf("%s is not running: %w", name, err)
+		}
 
-	// Step 2: Query ngrok API for active tunnels with retry logic
-	logger.Info("ngrok is running, fetching public URL...")
+		logger.Infof("%s is running, fetching public URL...", name)
+	}
 
-	publicURL, err := getPublicNgrokURLWithRetry(ctx)
+	// Step 2: Query the agent for active tunnels with retry logic
+	chosen, err := getPublicURLWithRetry(ctx, provider)
 	if err != nil {
 		// Add context about URL retrieval failure
-		return "", fmt.Errorf("failed to get public ngrok URL: %w", err)
+		return tunnel.Tunnel{}, fmt.Errorf("failed to get public %s URL: %w", name, err)
 	}
 
-	// Step 3: Display the selected ngrok URL
-	logger.Infof("Public ngrok URL: %s", publicURL)
+	// Step 3: Display the selected public URL
+	logger.Infof("Public %s URL: %s", name, chosen.PublicURL)
 
-	return publicURL, nil
+	return chosen, nil
 }
 
 // destinationStats tracks the outcome of destination update operations.
@@ -463,19 +448,22 @@ type destinationStats struct {
 	// skipped counts destinations that were not updated (user declined or errors occurred)
 	skipped int
 
-	// unchanged counts destinations that already had the correct ngrok URL
+	// unchanged counts destinations that already had the correct tunnel URL
 	unchanged int
 
-	// updated counts destinations that were successfully updated with the new ngrok URL
+	// updated counts destinations that were successfully updated with the new tunnel URL
 	updated int
+
+	// synced holds the destinations that now point at the tunnel, with their new URLs
+	synced []Destination
 }
 
 // updateDestinations processes a list of destinations and updates their URLs to point
-// to the provided ngrok public URL. For each destination, it checks if an update is needed,
-// prompts for user confirmation (unless skipped), and performs the update via the API.
+// to the provided tunnel's public URL. For each destination, it checks if an update is needed,
+// prompts for user confirmation (if confirm is set and --yes isn't), and performs the update via the API.
 // Returns statistics about the update operation (updated, skipped, unchanged counts).
 func updateDestinations(ctx context.Context, client *request.APIClient,
-	dests []Destination, publicURL string,
+	dests []Destination, publicURL string, confirm bool,
 ) destinationStats {
 	// Initialize statistics tracking
 	stats := destinationStats{}
@@ -496,6 +484,14 @@ func updateDestinations(ctx context.Context, client *request.APIClient,
 		// Skip destinations that already have the correct URL
 		if dest.URL == mergedURL {
 			stats.unchanged++
+			stats.synced = append(stats.synced, dest)
+
+			continue
+		}
+
+		// Destinations confirmed earlier in a --watch session are updated without asking again
+		if !confirm {
+			stats.record(ctx, client, dest, publicURL, mergedURL)
 
 			continue
 		}
@@ -520,45 +516,64 @@ func updateDestinations(ctx context.Context, client *request.APIClient,
 		}
 
 		// Attempt to update the destination via API
+		stats.record(ctx, client, dest, publicURL, mergedURL)
+	}
 
-		err = updateDestination(ctx, client, dest, publicURL)
-		if err != nil {
-			// Log API update failures but continue with other destinations
-			logger.Infof("failed to update destination %s: %v", dest.NameOrId(), err)
+	return stats
+}
 
-			stats.skipped++
+// record updates a destination via the API and counts the outcome.
+func (s *destinationStats) record(ctx context.Context, client *request.APIClient,
+	dest Destination, publicURL, mergedURL string,
+) {
+	err := updateDestination(ctx, client, dest, publicURL)
+	if err != nil {
+		// Log API update failures but continue with other destinations
+		logger.Infof("failed to update destination %s: %v", dest.NameOrId(), err)
 
-			continue
-		}
+		s.skipped++
 
-		// Successfully updated this destination
-		stats.updated++
+		return
 	}
 
-	return stats
+	// Successfully updated this destination
+	s.updated++
+
+	dest.URL = mergedURL
+	s.synced = append(s.synced, dest)
 }
 
 // updateDestination performs the actual API call to update a single destination's URL.
 // It uses the PATCH endpoint to modify only the metadata.url field of the destination,
 // preserving all other destination configuration. The URL is constructed by merging
-// the ngrok URL's protocol, host, and port with the existing URL's path and query parameters.
+// the tunnel URL's protocol, host, and port with the existing URL's path and query parameters.
 // Returns an error if the API call fails.
 func updateDestination(ctx context.Context, client *request.APIClient, dest Destination, publicURL string) error {
-	// Merge the ngrok URL with the existing destination URL to preserve path and query params
+	// Merge the tunnel URL with the existing destination URL to preserve path and query params
 	mergedURL, err := mergeURLs(publicURL, dest.URL)
 	if err != nil {
 		return fmt.Errorf("failed to merge URLs: %w", err)
 	}
 
+	// Remember the current URL first, so that the destination can be restored later
+	err = recordOriginal(client, dest, mergedURL)
+	if err != nil {
+		return fmt.Errorf("failed to record the original URL: %w", err)
+	}
+
 	// Log the update operation for user visibility
 	logger.Infof("Changing webhook destination %s to %s", dest.NameOrId(), mergedURL)
 
-	// Make API call to update the destination's URL
+	return patchDestinationURL(ctx, client, dest.Id, mergedURL)
+}
+
+// patchDestinationURL sets the URL of a destination, leaving the rest of its configuration as is.
+func patchDestinationURL(ctx context.Context, client *request.APIClient, id, newURL string) error {
 	// Using PATCH with update mask to only modify the URL field
-	_, err = client.PatchDestination(ctx, dest.Id, &request.PatchDestination{
+	_, err := client.PatchDestination(ctx, id, &request.PatchDestination{
 		Destination: map[string]any{
 			"metadata": map[string]any{
-				"url": mergedURL,
+				"url": newURL,
 			},
 		},
 		UpdateMask: []string{"metadata.url"}, // Only update the URL field
@@ -569,21 +584,21 @@ func updateDestination(ctx context.Context, client *request.APIClient, dest Dest
 
 // logDestinationStats outputs a summary of the destination update operation.
 // It provides a clear breakdown of how many destinations were processed in each category,
-// helping users understand the results of the ngrok command execution.
+// helping users understand the results of the sync.
 func logDestinationStats(stats destinationStats, total int) {
 	logger.Infof("Total destinations: %d, unchanged: %d, skipped: %d, updated: %d",
 		total, stats.unchanged, stats.skipped, stats.updated)
 }
 
-// mergeURLs combines a new base URL (from ngrok) with the path and query parameters
+// mergeURLs combines a new base URL (from the tunnel) with the path and query parameters
 // from an existing destination URL. This preserves the existing URL's path and query
-// components while updating the protocol, host, and port to match the ngrok tunnel.
+// components while updating the protocol, host, and port to match the tunnel.
 // Returns the merged URL string or an error if URL parsing fails.
-func mergeURLs(ngrokURL, existingURL string) (string, error) {
-	// Parse the ngrok URL to get the new base (protocol, host, port)
-	baseURL, err := url.Parse(ngrokURL)
+func mergeURLs(tunnelURL, existingURL string) (string, error) {
+	// Parse the tunnel URL to get the new base (protocol, host, port)
+	baseURL, err := url.Parse(tunnelURL)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse ngrok URL '%s': %w", ngrokURL, err)
+		return "", fmt.Errorf("failed to parse tunnel URL '%s': %w", tunnelURL, err)
 	}
 
 	// Parse the existing destination URL to get path and query components
@@ -592,7 +607,7 @@ func mergeURLs(ngrokURL, existingURL string) (string, error) {
 		return "", fmt.Errorf("failed to parse existing URL '%s': %w", existingURL, err)
 	}
 
-	// Create merged URL: use ngrok's scheme, host, and port with existing path and query
+	// Create merged URL: use the tunnel's scheme, host, and port with existing path and query
 	mergedURL := &url.URL{
 		Scheme:   baseURL.Scheme,
 		Host:     baseURL.Host,
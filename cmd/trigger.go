@@ -6,13 +6,17 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
+	neturl "net/url"
 	"os"
 	"os/exec"
-	"path/filepath"
+	"os/signal"
 	"runtime"
-	"strconv"
+	"sort"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/amp-labs/cli/internal/webhook"
@@ -22,10 +26,27 @@ import (
 
 var (
 	ErrInvalidEventFormat = errors.New("invalid event format, expected 'provider.event'")
+	errInvalidHeader      = errors.New("invalid header, expected 'Name: value'")
+	errMissingEvent       = errors.New("please provide an event, e.g. 'amp trigger hubspot.contact.created'")
+	errHARWithEvent       = errors.New("an event can't be given together with --har")
+	errInvalidCount       = errors.New("--count and --concurrency must be at least 1")
+	errRateTooHigh        = errors.New("--rate is too high, leave it out to send as fast as possible")
 	fixtureFile           string
 	rawJSON               string
 	interactive           bool
 	listenPort            string
+	triggerSession        string
+	triggerURL            string
+	triggerTimeout        time.Duration
+	triggerCount          int
+	triggerConcurrency    int
+	triggerRate           string
+	triggerMethod         string
+	triggerHeaderSpecs    []string
+	triggerHeaders        http.Header
+	deliveryHeaders       bool
+	signingSecret         string
+	harReplayFile         string
 	triggerCommand        = &cobra.Command{
 		Use:   "trigger [provider.event]",
 		Short: "Trigger a webhook event",
@@ -33,13 +54,38 @@ var (
 This command sends a webhook event to the local listener using fixture data.
 You can specify a built-in event or provide your own JSON payload.
 
+Use --url to send to any URL instead, such as a staging deployment of your handler.
+With --delivery-headers, the request carries the same webhook-id, webhook-timestamp
+and (given --signing-secret) webhook-signature headers as a real Ampersand delivery,
+so that signature verification in your handler can be tested too.
+
 Examples:
   amp trigger stripe.payment_intent.created
   amp trigger hubspot.contact.created --interactive
   amp trigger stripe.payment_intent.created --fixture ./my-custom-event.json
-  amp trigger custom.event --raw '{"key": "value"}'`,
+  amp trigger custom.event --raw '{"key": "value"}'
+  amp trigger hubspot.contact.created --session billing
+  amp trigger hubspot.contact.created --url https://staging.example.com/webhook \
+    --header 'Authorization: Bearer abc' --delivery-headers --signing-secret 'whsec_<base64-secret>'
+
+Load testing:
+  With --count, the payload is sent many times, --concurrency requests at a time and
+  at most --rate per second (or per minute, e.g. 600/m), and a report of latency
+  percentiles, status codes and errors is printed at the end. Payloads may contain
+  {{SEQ}} (the request number), {{UUID}} and {{NOW}}, which are filled in per request.
+
+  amp trigger salesforce.read --count 1000 --concurrency 20 --rate 100/s
+  amp trigger custom.event --raw '{"id": "rec-{{SEQ}}"}' --count 50 --url http://localhost:4000/webhook
+
+Replaying a HAR file:
+  With --har, the requests in an HTTP Archive file are sent again, in order, with their
+  original method, path, headers and body. For files written by 'amp listen --har',
+  only the requests the listener received are replayed, not the copies it forwarded.
+
+  amp trigger --har ./repro.har
+  amp trigger --har ./repro.har --url http://localhost:4000`,
 		Hidden: true,
-		Args:   cobra.ExactArgs(1),
+		Args:   cobra.MaximumNArgs(1),
 		RunE:   runTrigger,
 	}
 )
@@ -49,10 +95,51 @@ func init() {
 	triggerCommand.Flags().StringVar(&rawJSON, "raw", "", "Raw JSON payload to send")
 	triggerCommand.Flags().BoolVar(&interactive, "interactive", false, "Open editor before sending")
 	triggerCommand.Flags().StringVar(&listenPort, "port", "", "Port of the local listener (default: auto-detect)")
+	triggerCommand.Flags().StringVar(&triggerURL, "url", "",
+		"Send directly to this URL instead of to a local listener")
+	triggerCommand.Flags().StringVarP(&triggerMethod, "method", "X", http.MethodPost, "HTTP method to use")
+	triggerCommand.Flags().StringArrayVarP(&triggerHeaderSpecs, "header", "H", nil,
+		"Header to send, as 'Name: value' (can be repeated)")
+	triggerCommand.Flags().BoolVar(&deliveryHeaders, "delivery-headers", false,
+		"Add the webhook-id, webhook-timestamp and webhook-signature headers of Ampersand deliveries")
+	triggerCommand.Flags().StringVar(&signingSecret, "signing-secret", "",
+		"Secret (whsec_...) to sign the payload with, implies --delivery-headers")
+	triggerCommand.Flags().DurationVar(&triggerTimeout, "timeout", 5*time.Second, //nolint:mnd
+		"How long to wait for each response")
+	triggerCommand.Flags().IntVar(&triggerCount, "count", 1, "Number of times to send the webhook")
+	triggerCommand.Flags().IntVar(&triggerConcurrency, "concurrency", 1,
+		"Maximum number of webhooks in flight at once when using --count")
+	triggerCommand.Flags().StringVar(&triggerRate, "rate", "",
+		"Maximum send rate when using --count, e.g. 50/s or 600/m (default: unlimited)")
+	triggerCommand.Flags().StringVar(&harReplayFile, "har", "",
+		"Replay the requests in this HAR file instead of sending an event")
+	triggerCommand.MarkFlagsMutuallyExclusive("har", "raw")
+	triggerCommand.MarkFlagsMutuallyExclusive("har", "fixture")
+	triggerCommand.MarkFlagsMutuallyExclusive("har", "interactive")
+	triggerCommand.MarkFlagsMutuallyExclusive("har", "count")
+	triggerCommand.Flags().StringVar(&triggerSession, "session", "",
+		"Name of the 'amp listen' session to send to (default: the only running listener, or \"default\")")
 	rootCmd.AddCommand(triggerCommand)
 }
 
 func runTrigger(cmd *cobra.Command, args []string) error {
+	if harReplayFile != "" {
+		if len(args) > 0 {
+			return errHARWithEvent
+		}
+
+		return runTriggerHAR(cmd.Context())
+	}
+
+	if len(args) == 0 {
+		return errMissingEvent
+	}
+
+	loadOpts, err := loadOptions()
+	if err != nil {
+		return err
+	}
+
 	eventName := args[0]
 	provider, event := webhook.ParseEvent(eventName)
 
@@ -63,8 +150,6 @@ func runTrigger(cmd *cobra.Command, args []string) error {
 	// Determine which payload to use
 	var payload []byte
 
-	var err error
-
 	switch {
 	case rawJSON != "":
 		// Use raw JSON provided via command line
@@ -99,10 +184,186 @@ func runTrigger(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	triggerHeaders, err = parseHeaders(triggerHeaderSpecs)
+	if err != nil {
+		return err
+	}
+
+	if signingSecret != "" {
+		// Catch a bad secret now rather than on every request
+		_, err = webhook.Sign(signingSecret, "msg_check", time.Now(), payload)
+		if err != nil {
+			return err
+		}
+
+		deliveryHeaders = true
+	}
+
+	url, client, err := triggerTarget(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	if triggerCount > 1 {
+		return runTriggerLoad(cmd.Context(), loadOpts, eventName, url, client, payload)
+	}
+
 	// Send the webhook
 	fmt.Fprint(os.Stdout, "🚀 Triggering webhook: "+eventName+"\n")
 
-	return sendWebhook(payload)
+	return sendWebhook(cmd.Context(), client, url, webhook.RenderPayload(payload, 1))
+}
+
+// runTriggerHAR replays the requests of the --har file in order.
+func runTriggerHAR(ctx context.Context) error {
+	har, err := webhook.ReadHAR(harReplayFile)
+	if err != nil {
+		return err
+	}
+
+	triggerHeaders, err = parseHeaders(triggerHeaderSpecs)
+	if err != nil {
+		return err
+	}
+
+	target, client, err := triggerTarget(ctx)
+	if err != nil {
+		return err
+	}
+
+	entries := har.ReplayEntries()
+	fmt.Fprintf(os.Stdout, "📼 Replaying %d request(s) from %s → %s\n", len(entries), harReplayFile, target)
+
+	for idx, entry := range entries {
+		url, err := replayURL(target, entry.Request.URL)
+		if err != nil {
+			return err
+		}
+
+		progress := fmt.Sprintf("[%d/%d] %s %s", idx+1, len(entries), entry.Request.Method, url)
+
+		status, err := sendRequest(ctx, client, entry.Request.Method, url, entry.Request.Header(), entry.Request.Body())
+		if err != nil {
+			fmt.Fprint(os.Stdout, "❌ "+progress+" → failed: "+err.Error()+"\n")
+
+			continue
+		}
+
+		fmt.Fprint(os.Stdout, "✅ "+progress+" → "+statusLine(status)+"\n")
+	}
+
+	return nil
+}
+
+// replayURL returns where to send a recorded request: to target itself if it has a path,
+// otherwise to the recorded request's path and query on target's host.
+func replayURL(target, recorded string) (string, error) {
+	targetURL, err := neturl.Parse(target)
+	if err != nil {
+		return "", fmt.Errorf("invalid target URL: %w", err)
+	}
+
+	if targetURL.Path != "" && targetURL.Path != "/" {
+		return target, nil
+	}
+
+	recordedURL, err := neturl.Parse(recorded)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL in HAR file: %w", err)
+	}
+
+	targetURL.Path = recordedURL.Path
+	targetURL.RawPath = recordedURL.RawPath
+	targetURL.RawQuery = recordedURL.RawQuery
+
+	return targetURL.String(), nil
+}
+
+// loadOptions checks the --count, --concurrency and --rate flags.
+func loadOptions() (webhook.LoadOptions, error) {
+	opts := webhook.LoadOptions{Count: triggerCount, Concurrency: triggerConcurrency}
+
+	if triggerCount < 1 || triggerConcurrency < 1 {
+		return opts, errInvalidCount
+	}
+
+	if triggerRate != "" {
+		rate, err := webhook.ParseRate(triggerRate)
+		if err != nil {
+			return opts, err
+		}
+
+		if rate > webhook.MaxRate {
+			return opts, errRateTooHigh
+		}
+
+		opts.Rate = rate
+	}
+
+	return opts, nil
+}
+
+// runTriggerLoad sends the payload --count times and prints a report of the results.
+func runTriggerLoad(
+	ctx context.Context, opts webhook.LoadOptions, eventName, url string, client *http.Client, payload []byte,
+) error {
+	// Stop early on Ctrl+C, but still report what was sent
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Fprintf(os.Stdout, "🚀 Triggering %s %d times → %s\n", eventName, triggerCount, url)
+
+	report := webhook.RunLoad(ctx, opts, func(ctx context.Context, seq int) (int, error) {
+		return postWebhook(ctx, client, url, webhook.RenderPayload(payload, seq))
+	})
+
+	printLoadReport(report)
+
+	return nil
+}
+
+func printLoadReport(report *webhook.LoadReport) {
+	fmt.Fprintf(os.Stdout, "📊 Sent %d webhooks in %s (%.1f/s)\n",
+		report.Sent, report.Duration.Round(time.Millisecond), report.Throughput())
+
+	if len(report.Latencies) > 0 {
+		percentiles := []string{"min " + report.Latencies[0].Round(time.Microsecond).String()}
+
+		for _, pct := range []int{50, 90, 95, 99} {
+			percentiles = append(percentiles, fmt.Sprintf("p%d %s",
+				pct, report.Percentile(float64(pct)/100).Round(time.Microsecond))) //nolint:mnd
+		}
+
+		percentiles = append(percentiles, "max "+report.Latencies[len(report.Latencies)-1].Round(time.Microsecond).String())
+		fmt.Fprint(os.Stdout, "   Latency  "+strings.Join(percentiles, " · ")+"\n")
+	}
+
+	codes := make([]int, 0, len(report.Statuses))
+	for code := range report.Statuses {
+		codes = append(codes, code)
+	}
+
+	sort.Ints(codes)
+
+	statuses := make([]string, 0, len(codes))
+	for _, code := range codes {
+		statuses = append(statuses, fmt.Sprintf("%s × %d", statusLine(code), report.Statuses[code]))
+	}
+
+	if len(statuses) > 0 {
+		fmt.Fprint(os.Stdout, "   Status   "+strings.Join(statuses, " · ")+"\n")
+	}
+
+	messages := make([]string, 0, len(report.Errors))
+	for msg := range report.Errors {
+		messages = append(messages, msg)
+	}
+
+	sort.Strings(messages)
+
+	for _, msg := range messages {
+		fmt.Fprintf(os.Stdout, "   Error    %d × %s\n", report.Errors[msg], msg)
+	}
 }
 
 // openInEditor opens the JSON payload in the default editor.
@@ -154,56 +415,160 @@ func openInEditor(ctx context.Context, data []byte) ([]byte, error) {
 	return os.ReadFile(tmpFile.Name())
 }
 
-func sendWebhook(payload []byte) error {
-	port := getListenerPort()
+func sendWebhook(ctx context.Context, client *http.Client, url string, payload []byte) error {
+	status, err := postWebhook(ctx, client, url, payload)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook: %w", err)
+	}
+
+	fmt.Fprint(os.Stdout, "✅ Sent webhook → "+statusLine(status)+"\n")
 
-	url := "http://127.0.0.1:" + port
+	return nil
+}
 
-	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, url, bytes.NewReader(payload))
+// postWebhook sends a payload with the method and headers given by the flags, and returns the response status.
+func postWebhook(ctx context.Context, client *http.Client, url string, payload []byte) (int, error) {
+	return sendRequest(ctx, client, triggerMethod, url, nil, payload)
+}
+
+// hopHeaders are not copied from recorded requests, since they describe the original connection.
+var hopHeaders = []string{ //nolint:gochecknoglobals
+	"Host", "Content-Length", "Connection", "Transfer-Encoding", "Accept-Encoding",
+}
+
+// sendRequest sends a payload with the given headers, plus those given by the flags, and returns
+// the response status.
+func sendRequest(
+	ctx context.Context, client *http.Client, method, url string, header http.Header, payload []byte,
+) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, strings.ToUpper(method), url, bytes.NewReader(payload))
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return 0, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	if header != nil {
+		req.Header = header.Clone()
+		for _, name := range hopHeaders {
+			req.Header.Del(name)
+		}
+	}
 
-	// Send the request
-	const clientTimeout = 5 * time.Second
+	if req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
 
-	client := &http.Client{Timeout: clientTimeout}
+	if deliveryHeaders {
+		metadata, err := webhook.DeliveryHeaders(payload, signingSecret, time.Now())
+		if err != nil {
+			return 0, err
+		}
+
+		for name, values := range metadata {
+			req.Header[name] = values
+		}
+	}
+
+	// Headers given on the command line take precedence
+	for name, values := range triggerHeaders {
+		req.Header[name] = values
+	}
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to send webhook: %w", err)
+		return 0, err
 	}
 
 	defer resp.Body.Close()
 
-	fmt.Fprint(os.Stdout, "✅ Sent webhook → "+strconv.Itoa(resp.StatusCode)+" "+resp.Status+"\n")
+	// Drain the body so that the connection can be reused
+	_, _ = io.Copy(io.Discard, resp.Body)
 
-	return nil
+	return resp.StatusCode, nil
 }
 
-func getListenerPort() string {
-	if listenPort != "" {
-		return listenPort
+// parseHeaders parses --header values, curl style.
+func parseHeaders(specs []string) (http.Header, error) {
+	header := http.Header{}
+
+	for _, spec := range specs {
+		name, value, ok := strings.Cut(spec, ":")
+		name = strings.TrimSpace(name)
+
+		if !ok || name == "" {
+			return nil, fmt.Errorf("%w: %q", errInvalidHeader, spec)
+		}
+
+		header.Add(name, strings.TrimSpace(value))
 	}
 
-	// Try to get the port from the port file
-	dir, err := os.UserCacheDir()
+	return header, nil
+}
+
+// triggerTarget returns the URL to send webhooks to, and a client set up to reach it.
+func triggerTarget(ctx context.Context) (string, *http.Client, error) {
+	transport, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		transport = &http.Transport{}
+	}
+
+	transport = transport.Clone()
+	transport.MaxIdleConnsPerHost = max(triggerConcurrency, transport.MaxIdleConnsPerHost)
+
+	client := &http.Client{Timeout: triggerTimeout, Transport: transport}
+
+	if triggerURL != "" {
+		return triggerURL, client, nil
+	}
+
+	session, err := getListenerSession(ctx)
 	if err != nil {
-		logger.Debugf("error getting user cache dir: %v", err)
+		return "", nil, err
+	}
+
+	// HTTPS listeners use a certificate from the local CA
+	if session.CAFile != "" {
+		tlsConfig, err := webhook.ClientTLSConfig(session.CAFile)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to load the listener's CA: %w", err)
+		}
 
-		return "4242" // Default fallback port
+		transport.TLSClientConfig = tlsConfig
 	}
 
-	portFile := filepath.Join(dir, "ampersand", "webhook-port")
+	return session.URL(), client, nil
+}
 
-	data, err := os.ReadFile(portFile)
+// getListenerSession returns the listener to send to: the one on --port if given,
+// otherwise the running session picked by --session.
+func getListenerSession(ctx context.Context) (*webhook.Session, error) {
+	registry, err := webhook.DefaultRegistry()
 	if err != nil {
-		logger.Debug("could not find webhook port file, using default port 4242")
+		return nil, fmt.Errorf("failed to open listener registry: %w", err)
+	}
+
+	if listenPort != "" {
+		// Look up the session on that port to know whether it serves HTTPS
+		sessions, err := registry.Live(ctx)
+		if err != nil {
+			return nil, err
+		}
 
-		return "4242" // Default fallback port
+		for _, session := range sessions {
+			_, port, _ := net.SplitHostPort(session.Address)
+			if port == listenPort {
+				return session, nil
+			}
+		}
+
+		return &webhook.Session{Address: "127.0.0.1:" + listenPort}, nil
 	}
 
-	return strings.TrimSpace(string(data))
+	session, err := registry.Find(ctx, triggerSession)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debugf("sending to session %q at %s (pid %d)", session.Name, session.Address, session.PID)
+
+	return session, nil
 }
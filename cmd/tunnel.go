@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"github.com/amp-labs/cli/internal/tunnel"
+	"github.com/spf13/cobra"
+)
+
+var (
+	tunnelProvider           string //nolint:gochecknoglobals
+	tunnelCloudflaredMetrics string //nolint:gochecknoglobals
+	tunnelStaticURL          string //nolint:gochecknoglobals
+
+	tunnelCommand = &cobra.Command{ //nolint:gochecknoglobals
+		Use:    "tunnel",
+		Short:  "Work with tunnels that expose your machine to Ampersand",
+		Hidden: true,
+	}
+
+	tunnelSyncCommand = &cobra.Command{ //nolint:gochecknoglobals
+		Use:   "sync",
+		Short: "Point Ampersand webhook destinations at a local tunnel",
+		Long: `Point Ampersand webhook destinations at the public URL of a local tunnel.
+The scheme, host and port of each destination's URL are replaced with the tunnel's,
+while its path and query are kept. The original URLs are remembered, and are put back
+by --restore, or on Ctrl+C with --restore-on-exit. With --watch, the command keeps
+running and updates the destinations whenever the tunnel's URL changes.
+
+Providers:
+  ngrok        reads the tunnels of the ngrok agent API (--ngrok-server, default localhost:4040)
+  cloudflared  reads the quick tunnel hostname from cloudflared's metrics server
+               (--cloudflared-metrics); start cloudflared with --metrics localhost:20241
+  static       uses the URL given by --url as is
+
+Examples:
+  amp tunnel sync -D my-destination
+  amp tunnel sync --provider cloudflared -D my-destination
+  amp tunnel sync --url https://dev.example.com -D my-destination
+  amp tunnel sync --provider cloudflared -D my-destination --watch --restore-on-exit
+  amp tunnel sync --restore`,
+		Args: cobra.NoArgs,
+		RunE: runTunnelSync,
+	}
+)
+
+func init() {
+	tunnelSyncCommand.Flags().StringSliceVarP(&destinations, "destination", "D", []string{},
+		"Destination names or IDs (UUIDs)")
+	tunnelSyncCommand.Flags().BoolVarP(&skipConfirm, "yes", "y", false, "Skip confirmation prompts")
+	addRestoreFlags(tunnelSyncCommand)
+	addWatchFlags(tunnelSyncCommand)
+	tunnelSyncCommand.Flags().StringVar(&tunnelProvider, "provider", "",
+		"Tunnel provider: ngrok, cloudflared or static (default: static if --url is given, ngrok otherwise)")
+	tunnelSyncCommand.Flags().StringVarP(&ngrokServer, "ngrok-server", "s", "localhost:4040", "Ngrok server URL")
+	tunnelSyncCommand.Flags().StringVarP(&ngrokProtocol, "protocol", "P", "http",
+		"Protocol to use for ngrok server (http or https)")
+	tunnelSyncCommand.Flags().StringVar(&tunnelCloudflaredMetrics, "cloudflared-metrics", "localhost:20241",
+		"Address of cloudflared's metrics server")
+	tunnelSyncCommand.Flags().StringVar(&tunnelStaticURL, "url", "", "Public URL for the static provider")
+
+	tunnelCommand.AddCommand(tunnelSyncCommand)
+	rootCmd.AddCommand(tunnelCommand)
+}
+
+func runTunnelSync(cmd *cobra.Command, _ []string) error {
+	if restoreOnly {
+		return runRestore(cmd.Context())
+	}
+
+	provider, err := newTunnelProvider()
+	if err != nil {
+		return err
+	}
+
+	return syncTunnel(cmd.Context(), provider)
+}
+
+// newTunnelProvider returns the provider chosen by the flags of 'amp tunnel sync'.
+func newTunnelProvider() (tunnel.Provider, error) {
+	name := tunnelProvider
+	if name == "" {
+		name = tunnel.Ngrok
+
+		if tunnelStaticURL != "" {
+			name = tunnel.Static
+		}
+	}
+
+	if name == tunnel.Ngrok {
+		return newNgrokProvider()
+	}
+
+	return tunnel.New(name, tunnel.Config{
+		CloudflaredMetrics: tunnelCloudflaredMetrics,
+		StaticURL:          tunnelStaticURL,
+	})
+}
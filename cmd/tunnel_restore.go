@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/amp-labs/cli/flags"
+	"github.com/amp-labs/cli/internal/tunnel"
+	"github.com/amp-labs/cli/logger"
+	"github.com/amp-labs/cli/request"
+	"github.com/spf13/cobra"
+)
+
+// restoreTimeout bounds the API calls made to restore destinations after an interrupt.
+const restoreTimeout = 30 * time.Second
+
+// addRestoreFlags adds the flags shared by the commands that point destinations at a tunnel.
+func addRestoreFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&restoreOnExit, "restore-on-exit", false,
+		"Keep running after the sync and restore the destinations' original URLs on Ctrl+C")
+	cmd.Flags().BoolVar(&restoreOnly, "restore", false,
+		"Restore the original URLs of destinations changed by earlier syncs (all of them unless -D is given)")
+	cmd.MarkFlagsMutuallyExclusive("restore-on-exit", "restore")
+}
+
+// recordOriginal remembers the URL a destination has before it is pointed at tunnelURL.
+func recordOriginal(client *request.APIClient, dest Destination, tunnelURL string) error {
+	originals, err := tunnel.DefaultOriginals()
+	if err != nil {
+		return err
+	}
+
+	return originals.Record(tunnel.Original{
+		DestinationId: dest.Id,
+		Name:          dest.Name,
+		Project:       client.ProjectId,
+		URL:           dest.URL,
+		TunnelURL:     tunnelURL,
+		ChangedAt:     time.Now(),
+	})
+}
+
+// runRestore implements --restore: it puts back the destinations given by --destination,
+// or every destination of the project that a sync has changed.
+func runRestore(ctx context.Context) error {
+	projectId := flags.GetProjectOrFail()
+	apiKey := flags.GetAPIKey()
+	client := request.NewAPIClient(projectId, &apiKey)
+
+	var ids []string
+
+	if len(filterEmptyDestinations()) > 0 {
+		dests, err := getCanonicalDestinations(ctx, client)
+		if err != nil {
+			return fmt.Errorf("failed to get canonical destinations: %w", err)
+		}
+
+		ids = destinationIds(dests)
+	}
+
+	return restoreDestinations(ctx, client, ids)
+}
+
+// restoreOnInterrupt waits for the context to end, which happens on Ctrl+C or SIGTERM,
+// then restores the destinations that were synced.
+func restoreOnInterrupt(ctx context.Context, client *request.APIClient, dests []Destination) error {
+	// In watch mode, the session has already ended
+	if ctx.Err() == nil {
+		fmt.Fprint(os.Stdout, "⏳ Press Ctrl+C to restore the original destination URLs and exit\n")
+	}
+
+	<-ctx.Done()
+
+	// The API calls must outlive the interrupt that triggered them
+	restoreCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), restoreTimeout)
+	defer cancel()
+
+	return restoreDestinations(restoreCtx, client, destinationIds(dests))
+}
+
+// restoreDestinations sets the recorded destinations back to their original URLs. If ids is
+// empty, all destinations recorded for the project are restored. A destination whose URL was
+// changed by someone else since the sync is left alone.
+func restoreDestinations(ctx context.Context, client *request.APIClient, ids []string) error {
+	originals, err := tunnel.DefaultOriginals()
+	if err != nil {
+		return err
+	}
+
+	recorded, err := originals.List(client.ProjectId)
+	if err != nil {
+		return fmt.Errorf("failed to read original destination URLs: %w", err)
+	}
+
+	stats := destinationStats{}
+	total := 0
+
+	for _, orig := range recorded {
+		if len(ids) > 0 && !containsFold(ids, orig.DestinationId) {
+			continue
+		}
+
+		total++
+
+		restored, err := restoreDestination(ctx, client, orig)
+		if err != nil {
+			logger.Infof("failed to restore destination %s: %v", originalName(orig), err)
+
+			stats.skipped++
+
+			continue
+		}
+
+		// The record has served its purpose, whether or not the URL had to change
+		err = originals.Remove(orig.DestinationId)
+		if err != nil {
+			logger.Infof("failed to forget original URL of destination %s: %v", originalName(orig), err)
+		}
+
+		switch restored {
+		case restoreDone:
+			stats.updated++
+		case restoreUnchanged:
+			stats.unchanged++
+		case restoreSkipped:
+			stats.skipped++
+		}
+	}
+
+	if total == 0 {
+		logger.Info("No destinations to restore")
+
+		return nil
+	}
+
+	logger.Infof("Total destinations: %d, unchanged: %d, skipped: %d, restored: %d",
+		total, stats.unchanged, stats.skipped, stats.updated)
+
+	return nil
+}
+
+type restoreResult int
+
+const (
+	restoreDone restoreResult = iota
+	restoreUnchanged
+	restoreSkipped
+)
+
+// restoreDestination puts one destination back, unless its URL was changed since the sync.
+func restoreDestination(ctx context.Context, client *request.APIClient, orig *tunnel.Original) (restoreResult, error) {
+	current, err := client.GetDestination(ctx, orig.DestinationId)
+	if err != nil {
+		return restoreSkipped, err
+	}
+
+	currentURL := ""
+	if current.Metadata != nil {
+		currentURL = current.Metadata.URL
+	}
+
+	switch currentURL {
+	case orig.URL:
+		return restoreUnchanged, nil
+	case orig.TunnelURL:
+		logger.Infof("Restoring webhook destination %s to %s", originalName(orig), orig.URL)
+
+		err = patchDestinationURL(ctx, client, orig.DestinationId, orig.URL)
+		if err != nil {
+			return restoreSkipped, err
+		}
+
+		return restoreDone, nil
+	default:
+		logger.Infof("Destination %s was changed to %s since it was synced, leaving it as is",
+			originalName(orig), currentURL)
+
+		return restoreSkipped, nil
+	}
+}
+
+func originalName(orig *tunnel.Original) string {
+	if orig.Name != "" {
+		return orig.Name
+	}
+
+	return orig.DestinationId
+}
+
+func destinationIds(dests []Destination) []string {
+	ids := make([]string, 0, len(dests))
+	for _, dest := range dests {
+		ids = append(ids, dest.Id)
+	}
+
+	return ids
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+
+	return false
+}
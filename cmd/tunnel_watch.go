@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/amp-labs/cli/internal/tunnel"
+	"github.com/amp-labs/cli/logger"
+	"github.com/amp-labs/cli/request"
+	"github.com/spf13/cobra"
+)
+
+var errTunnelGone = errors.New("the tunnel is gone")
+
+var (
+	// watchTunnel keeps the command running and re-syncs the destinations whenever the
+	// tunnel's public URL changes.
+	watchTunnel bool //nolint:gochecknoglobals
+
+	// watchInterval is how often the tunnel agent is polled in watch mode.
+	watchInterval time.Duration //nolint:gochecknoglobals
+)
+
+// addWatchFlags adds the watch mode flags shared by the commands that point destinations at a tunnel.
+func addWatchFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&watchTunnel, "watch", false,
+		"Keep running and update the destinations whenever the tunnel's public URL changes")
+	cmd.Flags().DurationVar(&watchInterval, "watch-interval", 5*time.Second, //nolint:mnd
+		"How often to check the tunnel's public URL in watch mode")
+	cmd.MarkFlagsMutuallyExclusive("watch", "restore")
+}
+
+// watchTunnelURL polls the provider until the context ends, and points the destinations at the
+// new public URL of the chosen tunnel whenever it changes. The tunnel and the destinations
+// were confirmed by the initial sync, so they are followed without asking again.
+func watchTunnelURL(ctx context.Context, client *request.APIClient, provider tunnel.Provider,
+	chosen tunnel.Tunnel, dests []Destination,
+) {
+	if len(dests) == 0 {
+		logger.Info("No destinations point at the tunnel, nothing to watch")
+
+		return
+	}
+
+	fmt.Fprintf(os.Stdout, "👀 Watching %s for URL changes, press Ctrl+C to stop\n", provider.Name())
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	// missing is set while the tunnel can't be found, so that it is only reported once
+	missing := false
+
+	// syncedURL is the public URL that every destination points at. While some destinations
+	// couldn't be updated, they are retried on every tick.
+	syncedURL := chosen.PublicURL
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// The agent may be restarting, in which case the tunnel comes back with a new URL
+		next, err := findTunnel(ctx, provider, chosen)
+		if err != nil {
+			if !missing && ctx.Err() == nil {
+				logger.Infof("%v, waiting for it to come back", err)
+			}
+
+			missing = true
+
+			continue
+		}
+
+		missing = false
+
+		if next.PublicURL == syncedURL {
+			chosen = next
+
+			continue
+		}
+
+		if next.PublicURL != chosen.PublicURL {
+			logger.Infof("Public %s URL changed to %s", provider.Name(), next.PublicURL)
+		}
+
+		chosen = next
+		stats := updateDestinations(ctx, client, dests, chosen.PublicURL, false)
+		logDestinationStats(stats, len(dests))
+
+		// Keep the destinations' current URLs, so that only the out of date ones are updated next
+		for _, synced := range stats.synced {
+			idx := slices.IndexFunc(dests, func(dest Destination) bool { return dest.Id == synced.Id })
+			dests[idx].URL = synced.URL
+		}
+
+		if len(stats.synced) == len(dests) {
+			syncedURL = chosen.PublicURL
+		} else {
+			logger.Infof("%d destination(s) still point at the old URL, retrying in %s",
+				len(dests)-len(stats.synced), watchInterval)
+		}
+	}
+}
+
+// findTunnel returns the current state of the chosen tunnel, without prompting.
+func findTunnel(ctx context.Context, provider tunnel.Provider, chosen tunnel.Tunnel) (tunnel.Tunnel, error) {
+	tunnels, err := provider.Tunnels(ctx)
+	if err != nil {
+		return tunnel.Tunnel{}, fmt.Errorf("%s is not available: %w", provider.Name(), err)
+	}
+
+	next, ok := chosen.Find(tunnels)
+	if !ok {
+		return tunnel.Tunnel{}, fmt.Errorf("%w: %s", errTunnelGone, describeTunnel(chosen))
+	}
+
+	return next, nil
+}
+
+// describeTunnel names a tunnel in messages.
+func describeTunnel(chosen tunnel.Tunnel) string {
+	if chosen.Addr == "" {
+		return chosen.PublicURL
+	}
+
+	return chosen.PublicURL + " (" + chosen.Addr + ")"
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/amp-labs/cli/files"
+	"github.com/amp-labs/cli/internal/scaffold"
+	"github.com/amp-labs/cli/internal/schema"
+	"github.com/amp-labs/cli/logger"
+	"github.com/amp-labs/cli/openapi"
+	"github.com/spf13/cobra"
+)
+
+var validateCmd = &cobra.Command{ //nolint:gochecknoglobals
+	Use:   "validate [manifest]",
+	Short: "Check amp.yaml against the manifest schema",
+	Long: `Check a manifest against the JSON Schema of amp.yaml, which is the one editors use
+(see 'amp schema export'), and then for what a schema can't express, such as schedules
+that aren't valid cron expressions.
+
+The fields of read objects are also checked against the fields of the provider's objects,
+to catch typos: from --fields-file if given, or else from the API when a project is given,
+through a connection to the provider (see --group-ref). Modules are checked against the
+provider catalog.
+
+The manifest is amp.yaml by default, and a directory stands for the amp.yaml in it.`,
+	Args:   cobra.MaximumNArgs(1),
+	Hidden: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		path := "amp.yaml"
+		if len(args) > 0 {
+			path = args[0]
+		}
+
+		path = manifestPath(path)
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			logger.FatalErr("Unable to read "+path, err)
+		}
+
+		violations, err := schema.Validate(data)
+		if errors.Is(err, schema.ErrInvalid) {
+			for _, violation := range violations {
+				fmt.Fprintf(os.Stdout, "%s: %s\n", path, violation)
+			}
+
+			os.Exit(1)
+		}
+
+		if err != nil {
+			logger.FatalErr("Unable to validate "+path, err)
+		}
+
+		manifest, err := files.ParseManifest(data)
+		if err != nil {
+			logger.FatalErr("Unable to parse "+path, err)
+		}
+
+		err = files.ValidateManifest(manifest)
+		if err != nil {
+			fmt.Fprint(os.Stdout, err.Error()+"\n")
+			os.Exit(1)
+		}
+
+		err = checkManifestFields(cmd.Context(), manifest)
+		if errors.Is(err, errUnknownFields) {
+			fmt.Fprint(os.Stdout, err.Error()+"\n")
+			os.Exit(1)
+		}
+
+		if err != nil {
+			logger.FatalErr("Unable to check the fields of "+path, err)
+		}
+
+		err = checkManifestModules(cmd.Context(), manifest)
+		if errors.Is(err, ErrUnknownProvider) || errors.Is(err, scaffold.ErrUnknownModule) {
+			fmt.Fprint(os.Stdout, err.Error()+"\n")
+			os.Exit(1)
+		}
+
+		if err != nil {
+			logger.FatalErr("Unable to check the modules of "+path, err)
+		}
+
+		fmt.Fprintf(os.Stdout, "%s is valid\n", path)
+	},
+}
+
+// checkManifestModules checks the modules of the integrations against the provider catalog,
+// which is only fetched if some integration names a module.
+func checkManifestModules(ctx context.Context, manifest *openapi.Manifest) error {
+	var catalog openapi.CatalogType
+
+	var errs []error
+
+	for _, integ := range manifest.Integrations {
+		if integ.Module == "" {
+			continue
+		}
+
+		if catalog == nil {
+			var err error
+
+			catalog, err = getCatalog(ctx)
+			if err != nil {
+				return err
+			}
+		}
+
+		provider, ok := catalog[integ.Provider]
+		if !ok {
+			errs = append(errs, fmt.Errorf("integration %s: %w: %q", integ.Name, ErrUnknownProvider, integ.Provider))
+
+			continue
+		}
+
+		_, err := scaffold.ModuleProvider(&provider, integ.Module)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("integration %s: %w", integ.Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func init() {
+	addFieldSourceFlags(validateCmd)
+	rootCmd.AddCommand(validateCmd)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/amp-labs/cli/internal/schedule"
 	"github.com/amp-labs/cli/openapi"
 	"sigs.k8s.io/yaml"
 )
@@ -84,6 +85,25 @@ func validateRead(read *openapi.IntegrationRead, path *pathTracker) error {
 		if obj.Destination == "" {
 			return validationError(path.PushArr(idx).PushObj("destination"), "The field 'destination' is required")
 		}
+
+		err := validateSchedule(obj.Schedule, path.PushArr(idx).PushObj("schedule"))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateSchedule checks that a schedule is a valid cron expression, if there is one.
+func validateSchedule(expr string, path *pathTracker) error {
+	if expr == "" {
+		return nil
+	}
+
+	_, err := schedule.Parse(expr)
+	if err != nil {
+		return validationError(path, "%v", err)
 	}
 
 	return nil
@@ -246,6 +266,13 @@ func validateIntegration(integration openapi.Integration, path *pathTracker) err
 		}
 	}
 
+	if integration.WatchSchema != nil {
+		err := validateSchedule(integration.WatchSchema.Schedule, path.PushObj("watchSchema").PushObj("schedule"))
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
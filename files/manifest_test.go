@@ -1,6 +1,8 @@
 package files
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/amp-labs/cli/openapi"
@@ -125,3 +127,36 @@ func TestGetRemovedReadObjects(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateManifestSchedule(t *testing.T) {
+	t.Parallel()
+
+	manifest := &openapi.Manifest{
+		SpecVersion: manifestVersion,
+		Integrations: []openapi.Integration{{
+			Name:     "x",
+			Provider: "salesforce",
+			Read: &openapi.IntegrationRead{Objects: &[]openapi.IntegrationObject{
+				{ObjectName: "Account", Destination: "hook", Schedule: "*/10 * * * *"},
+				{ObjectName: "Contact", Destination: "hook"},
+				{ObjectName: "Lead", Destination: "hook", Schedule: "*/10 * * *"},
+			}},
+		}},
+	}
+
+	err := ValidateManifest(manifest)
+	if !errors.Is(err, ErrBadManifest) {
+		t.Fatalf("ValidateManifest() returned %v, want ErrBadManifest", err)
+	}
+
+	if !strings.Contains(err.Error(), `"$.integrations[0].read.objects[2].schedule"`) {
+		t.Errorf("ValidateManifest() returned %v, want the path of the schedule", err)
+	}
+
+	(*manifest.Integrations[0].Read.Objects)[2].Schedule = "@daily"
+
+	err = ValidateManifest(manifest)
+	if err != nil {
+		t.Errorf("ValidateManifest() returned %v", err)
+	}
+}
@@ -0,0 +1,250 @@
+// Package lint finds mistakes in manifests that are valid as far as the schema goes, such as
+// objects declared twice or schedules that don't parse.
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/amp-labs/cli/openapi"
+	yamlv3 "go.yaml.in/yaml/v3"
+	"sigs.k8s.io/yaml"
+)
+
+var ErrUnknownRule = errors.New("unknown lint rule")
+
+// Severity tells whether an issue is a mistake or only looks like one.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Rule is a check of the manifest. Its Id is what suppresses it.
+type Rule struct {
+	Id          string
+	Description string
+	Severity    Severity
+
+	check func(lint *linter)
+}
+
+// Issue is a place where the manifest breaks a rule.
+type Issue struct {
+	Rule *Rule
+
+	// Path is the YAML path of the value at fault, e.g. $.integrations[0].read.objects[1].schedule
+	Path Path
+
+	// Line is the line of the value at fault, counted from 1.
+	Line    int
+	Message string
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%d: %s: %s (%s)", i.Line, i.Rule.Severity, i.Message, i.Rule.Id)
+}
+
+// Options configure the linter.
+type Options struct {
+	// Disabled are the Ids of the rules to skip.
+	Disabled []string
+
+	// Destinations are the names of the destinations of the project, or nil if they are
+	// unknown, in which case destinations are not checked.
+	Destinations []string
+}
+
+// ignoreComment suppresses rules for the value on the line it is on, or on the next line if it
+// is alone on its line, and for everything nested in that value. Above the first key, it
+// suppresses them for the whole file: # amp:lint-ignore duplicate-object, missing-schedule
+var ignoreComment = regexp.MustCompile(`#\s*amp:lint-ignore\s+([\w\s,-]+)`) //nolint:gochecknoglobals
+
+// Lint checks a manifest against the rules, and returns the issues sorted by line.
+func Lint(data []byte, opts Options) ([]Issue, error) {
+	for _, id := range opts.Disabled {
+		if FindRule(id) == nil {
+			return nil, fmt.Errorf("%w %q", ErrUnknownRule, id)
+		}
+	}
+
+	var manifest openapi.Manifest
+
+	err := yaml.Unmarshal(data, &manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse yaml: %w", err)
+	}
+
+	var root yamlv3.Node
+
+	err = yamlv3.Unmarshal(data, &root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse yaml: %w", err)
+	}
+
+	lint := &linter{manifest: &manifest, opts: &opts, root: &root, ignored: ignoredRules(string(data), &root)}
+
+	for _, rule := range Rules {
+		if !slices.Contains(opts.Disabled, rule.Id) {
+			lint.rule = rule
+			rule.check(lint)
+		}
+	}
+
+	slices.SortStableFunc(lint.issues, func(a, b Issue) int {
+		return a.Line - b.Line
+	})
+
+	return lint.issues, nil
+}
+
+// FindRule returns the rule with the given Id, or nil.
+func FindRule(id string) *Rule {
+	for _, rule := range Rules {
+		if rule.Id == id {
+			return rule
+		}
+	}
+
+	return nil
+}
+
+// linter holds the state of a run of the rules.
+type linter struct {
+	manifest *openapi.Manifest
+	opts     *Options
+	root     *yamlv3.Node
+
+	// ignored are the rules suppressed by comments, by line
+	ignored map[int][]string
+
+	rule   *Rule
+	issues []Issue
+}
+
+// report adds an issue of the current rule, unless a comment suppresses it.
+func (l *linter) report(path Path, format string, args ...any) {
+	lines := path.lines(l.root)
+	for _, line := range lines {
+		if slices.Contains(l.ignored[line], l.rule.Id) {
+			return
+		}
+	}
+
+	line := lines[len(lines)-1]
+
+	l.issues = append(l.issues, Issue{
+		Rule:    l.rule,
+		Path:    path,
+		Line:    line,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func ignoredRules(data string, root *yamlv3.Node) map[int][]string {
+	ignored := map[int][]string{}
+
+	// Every issue is matched against the line of the root mapping, so comments above it,
+	// blank lines or not, cover the whole file
+	firstLine := 0
+	if root.Kind == yamlv3.DocumentNode && len(root.Content) > 0 {
+		firstLine = root.Content[0].Line
+	}
+
+	for idx, text := range strings.Split(data, "\n") {
+		match := ignoreComment.FindStringSubmatch(text)
+		if match == nil {
+			continue
+		}
+
+		line := idx + 1
+
+		switch {
+		case !strings.HasPrefix(strings.TrimSpace(text), "#"):
+		case line < firstLine:
+			line = firstLine
+		default:
+			line++
+		}
+
+		for _, id := range strings.FieldsFunc(match[1], func(r rune) bool { return r == ',' || r == ' ' }) {
+			ignored[line] = append(ignored[line], id)
+		}
+	}
+
+	return ignored
+}
+
+// Path is the YAML path of a value of the manifest, made of keys and indexes.
+type Path []any
+
+func (p Path) String() string {
+	var sb strings.Builder
+
+	sb.WriteString("$")
+
+	for _, part := range p {
+		switch part := part.(type) {
+		case int:
+			sb.WriteString("[" + strconv.Itoa(part) + "]")
+		default:
+			sb.WriteString(fmt.Sprintf(".%v", part))
+		}
+	}
+
+	return sb.String()
+}
+
+// Key returns the path to a key of the mapping at the path.
+func (p Path) Key(key string) Path {
+	return append(slices.Clip(p), key)
+}
+
+// Index returns the path to an item of the sequence at the path.
+func (p Path) Index(idx int) Path {
+	return append(slices.Clip(p), idx)
+}
+
+// lines returns the lines of the keys and values along the path, from the root to the value
+// at the path, or to its closest parent that exists.
+func (p Path) lines(root *yamlv3.Node) []int {
+	if root.Kind != yamlv3.DocumentNode || len(root.Content) == 0 {
+		return []int{1}
+	}
+
+	node := root.Content[0]
+	lines := []int{node.Line}
+
+	for _, part := range p {
+		var next *yamlv3.Node
+
+		switch part := part.(type) {
+		case int:
+			if node.Kind == yamlv3.SequenceNode && part < len(node.Content) {
+				next = node.Content[part]
+			}
+		case string:
+			for idx := 0; node.Kind == yamlv3.MappingNode && idx+1 < len(node.Content); idx += 2 {
+				if node.Content[idx].Value == part {
+					// Block values start on the line after their key
+					next = node.Content[idx+1]
+					lines = append(lines, node.Content[idx].Line)
+				}
+			}
+		}
+
+		if next == nil {
+			break
+		}
+
+		node = next
+		lines = append(lines, node.Line)
+	}
+
+	return lines
+}
@@ -0,0 +1,143 @@
+package lint
+
+import (
+	"errors"
+	"testing"
+)
+
+const manifest = `specVersion: 1.0.0
+integrations:
+- name: x
+  provider: salesforce
+  read:
+    objects:
+    - objectName: Account
+      destination: hook
+      schedule: "*/10 * * *"
+      backfill:
+        defaultPeriod: {days: 3, fullHistory: true}
+      requiredFields:
+      - fieldName: Id
+      optionalFields:
+      - fieldName: OwnerId
+        mapToName: id
+    - objectName: account
+      destination: elsewhere
+  subscribe:
+    objects:
+    - objectName: Account
+      destination: hook
+- name: x
+  provider: hubspot
+  proxy:
+    enabled: true
+`
+
+func ruleIds(issues []Issue) []string {
+	ids := make([]string, 0, len(issues))
+	for _, issue := range issues {
+		ids = append(ids, issue.Rule.Id)
+	}
+
+	return ids
+}
+
+func TestLint(t *testing.T) {
+	t.Parallel()
+
+	issues, err := Lint([]byte(manifest), Options{Destinations: []string{"hook"}})
+	if err != nil {
+		t.Fatalf("Lint() returned %v", err)
+	}
+
+	want := []struct {
+		id   string
+		line int
+		path string
+	}{
+		{"invalid-schedule", 9, "$.integrations[0].read.objects[0].schedule"},
+		{"backfill-conflict", 11, "$.integrations[0].read.objects[0].backfill.defaultPeriod"},
+		{"mapping-collision", 15, "$.integrations[0].read.objects[0].optionalFields[0]"},
+		{"duplicate-object", 17, "$.integrations[0].read.objects[1].objectName"},
+		{"missing-schedule", 17, "$.integrations[0].read.objects[1]"},
+		{"unknown-destination", 18, "$.integrations[0].read.objects[1].destination"},
+		{"duplicate-integration", 23, "$.integrations[1].name"},
+	}
+
+	if len(issues) != len(want) {
+		t.Fatalf("Lint() = %v, want %d issues", issues, len(want))
+	}
+
+	for idx, issue := range issues {
+		if issue.Rule.Id != want[idx].id || issue.Line != want[idx].line || issue.Path.String() != want[idx].path {
+			t.Errorf("issue %d is %s at line %d (%s), want %s at line %d (%s)", idx, issue.Rule.Id, issue.Line,
+				issue.Path, want[idx].id, want[idx].line, want[idx].path)
+		}
+	}
+
+	// Destinations are only checked when they are known
+	issues, err = Lint([]byte(manifest), Options{})
+	if err != nil {
+		t.Fatalf("Lint() returned %v", err)
+	}
+
+	for _, issue := range issues {
+		if issue.Rule.Id == "unknown-destination" {
+			t.Errorf("Lint() without destinations reported %s", issue)
+		}
+	}
+}
+
+func TestLintSuppression(t *testing.T) {
+	t.Parallel()
+
+	data := `specVersion: 1.0.0
+integrations:
+- name: x
+  provider: salesforce
+  read: # amp:lint-ignore backfill-conflict
+    objects:
+    # amp:lint-ignore duplicate-object, missing-schedule
+    - objectName: Account
+      destination: hook
+      backfill:
+        defaultPeriod: {days: 3, fullHistory: true}
+    - objectName: account
+      destination: hook
+      schedule: "bad" # amp:lint-ignore invalid-schedule
+`
+
+	issues, err := Lint([]byte(data), Options{Disabled: []string{"duplicate-object"}})
+	if err != nil {
+		t.Fatalf("Lint() returned %v", err)
+	}
+
+	if len(issues) != 0 {
+		t.Errorf("Lint() = %v, want every issue suppressed", ruleIds(issues))
+	}
+
+	// Comments above the first key cover the whole file, even when a blank line follows them
+	header := "# My integrations\n# amp:lint-ignore missing-schedule\n\n" + `specVersion: 1.0.0
+integrations:
+- name: x
+  provider: salesforce
+  read:
+    objects:
+    - objectName: Account
+      destination: hook
+`
+
+	issues, err = Lint([]byte(header), Options{})
+	if err != nil {
+		t.Fatalf("Lint() returned %v", err)
+	}
+
+	if len(issues) != 0 {
+		t.Errorf("Lint() with a file-wide comment = %v, want every issue suppressed", ruleIds(issues))
+	}
+
+	_, err = Lint([]byte(data), Options{Disabled: []string{"no-such-rule"}})
+	if !errors.Is(err, ErrUnknownRule) {
+		t.Errorf("Lint() with an unknown rule returned %v, want ErrUnknownRule", err)
+	}
+}
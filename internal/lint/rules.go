@@ -0,0 +1,286 @@
+package lint
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/amp-labs/cli/internal/schedule"
+	"github.com/amp-labs/cli/openapi"
+)
+
+// Rules are the checks that Lint runs, in order.
+var Rules = []*Rule{ //nolint:gochecknoglobals
+	{
+		Id:          "duplicate-integration",
+		Description: "Two integrations have the same name",
+		Severity:    SeverityError,
+		check:       checkDuplicateIntegrations,
+	},
+	{
+		Id:          "duplicate-object",
+		Description: "An object is declared twice in the same action, regardless of case",
+		Severity:    SeverityError,
+		check:       checkDuplicateObjects,
+	},
+	{
+		Id:          "unknown-destination",
+		Description: "A read or subscribe object sends to a destination the project doesn't have",
+		Severity:    SeverityError,
+		check:       checkDestinations,
+	},
+	{
+		Id:          "missing-schedule",
+		Description: "A read object has no schedule, so it is never read on its own",
+		Severity:    SeverityWarning,
+		check:       checkMissingSchedules,
+	},
+	{
+		Id:          "invalid-schedule",
+		Description: "A schedule is not a valid cron expression",
+		Severity:    SeverityError,
+		check:       checkSchedules,
+	},
+	{
+		Id:          "aggressive-schedule",
+		Description: "A schedule runs more often than every 10 minutes, or every hour for schema checks",
+		Severity:    SeverityWarning,
+		check:       checkAggressiveSchedules,
+	},
+	{
+		Id:          "backfill-conflict",
+		Description: "A backfill period sets both days and fullHistory",
+		Severity:    SeverityError,
+		check:       checkBackfills,
+	},
+	{
+		Id:          "mapping-collision",
+		Description: "Two fields of a read object map to the same name, so one of them can't be reached",
+		Severity:    SeverityError,
+		check:       checkMappingCollisions,
+	},
+}
+
+// eachReadObject calls fn with every read object of the manifest, and its path.
+func (l *linter) eachReadObject(fn func(obj *openapi.IntegrationObject, path Path)) {
+	for integIdx, integ := range l.manifest.Integrations {
+		if integ.Read == nil || integ.Read.Objects == nil {
+			continue
+		}
+
+		objects := *integ.Read.Objects
+		for idx := range objects {
+			fn(&objects[idx], Path{"integrations", integIdx, "read", "objects", idx})
+		}
+	}
+}
+
+// eachSubscribeObject calls fn with every subscribe object of the manifest, and its path.
+func (l *linter) eachSubscribeObject(fn func(obj *openapi.IntegrationSubscribeObject, path Path)) {
+	for integIdx, integ := range l.manifest.Integrations {
+		if integ.Subscribe == nil || integ.Subscribe.Objects == nil {
+			continue
+		}
+
+		objects := *integ.Subscribe.Objects
+		for idx := range objects {
+			fn(&objects[idx], Path{"integrations", integIdx, "subscribe", "objects", idx})
+		}
+	}
+}
+
+func checkDuplicateIntegrations(lint *linter) {
+	first := map[string]int{}
+
+	for idx, integ := range lint.manifest.Integrations {
+		if prev, ok := first[integ.Name]; ok {
+			lint.report(Path{"integrations", idx, "name"},
+				"integration %s is already declared at $.integrations[%d]", integ.Name, prev)
+
+			continue
+		}
+
+		first[integ.Name] = idx
+	}
+}
+
+func checkDuplicateObjects(lint *linter) {
+	for integIdx, integ := range lint.manifest.Integrations {
+		names := map[string][]string{}
+
+		if integ.Read != nil && integ.Read.Objects != nil {
+			for _, obj := range *integ.Read.Objects {
+				names["read"] = append(names["read"], obj.ObjectName)
+			}
+		}
+
+		if integ.Write != nil && integ.Write.Objects != nil {
+			for _, obj := range *integ.Write.Objects {
+				names["write"] = append(names["write"], obj.ObjectName)
+			}
+		}
+
+		if integ.Subscribe != nil && integ.Subscribe.Objects != nil {
+			for _, obj := range *integ.Subscribe.Objects {
+				names["subscribe"] = append(names["subscribe"], obj.ObjectName)
+			}
+		}
+
+		for _, action := range []string{"read", "write", "subscribe"} {
+			first := map[string]int{}
+
+			for idx, name := range names[action] {
+				key := strings.ToLower(name)
+				if prev, ok := first[key]; ok {
+					lint.report(Path{"integrations", integIdx, action, "objects", idx, "objectName"},
+						"%s object %s is already declared at objects[%d]", action, name, prev)
+
+					continue
+				}
+
+				first[key] = idx
+			}
+		}
+	}
+}
+
+func checkDestinations(lint *linter) {
+	if lint.opts.Destinations == nil {
+		return
+	}
+
+	check := func(destination string, path Path) {
+		if destination != "" && !slices.Contains(lint.opts.Destinations, destination) {
+			lint.report(path.Key("destination"), "the project has no destination named %s", destination)
+		}
+	}
+
+	lint.eachReadObject(func(obj *openapi.IntegrationObject, path Path) {
+		check(obj.Destination, path)
+	})
+
+	lint.eachSubscribeObject(func(obj *openapi.IntegrationSubscribeObject, path Path) {
+		check(obj.Destination, path)
+	})
+}
+
+func checkMissingSchedules(lint *linter) {
+	lint.eachReadObject(func(obj *openapi.IntegrationObject, path Path) {
+		if strings.TrimSpace(obj.Schedule) == "" {
+			lint.report(path, "read object %s has no schedule", obj.ObjectName)
+		}
+	})
+}
+
+// eachSchedule calls fn with every schedule of the manifest that is set, its path and the
+// shortest interval it may have between runs.
+func (l *linter) eachSchedule(fn func(expr string, path Path, minInterval time.Duration)) {
+	l.eachReadObject(func(obj *openapi.IntegrationObject, path Path) {
+		if strings.TrimSpace(obj.Schedule) != "" {
+			fn(obj.Schedule, path.Key("schedule"), schedule.MinReadInterval)
+		}
+	})
+
+	for idx, integ := range l.manifest.Integrations {
+		if integ.WatchSchema != nil && strings.TrimSpace(integ.WatchSchema.Schedule) != "" {
+			fn(integ.WatchSchema.Schedule, Path{"integrations", idx, "watchSchema", "schedule"},
+				schedule.MinWatchSchemaInterval)
+		}
+	}
+}
+
+func checkSchedules(lint *linter) {
+	lint.eachSchedule(func(expr string, path Path, _ time.Duration) {
+		_, err := schedule.Parse(expr)
+		if err != nil {
+			lint.report(path, "%v", err)
+		}
+	})
+}
+
+func checkAggressiveSchedules(lint *linter) {
+	lint.eachSchedule(func(expr string, path Path, minInterval time.Duration) {
+		sched, err := schedule.Parse(expr)
+		if err != nil {
+			return
+		}
+
+		if aggressive, interval := sched.TooAggressive(minInterval); aggressive {
+			lint.report(path, "schedule %q runs every %s at times, more often than every %s", expr,
+				schedule.FormatInterval(interval), schedule.FormatInterval(minInterval))
+		}
+	})
+}
+
+func checkBackfills(lint *linter) {
+	lint.eachReadObject(func(obj *openapi.IntegrationObject, path Path) {
+		if obj.Backfill == nil {
+			return
+		}
+
+		period := obj.Backfill.DefaultPeriod
+		if period.Days != nil && period.FullHistory != nil && *period.FullHistory {
+			lint.report(path.Key("backfill").Key("defaultPeriod"),
+				"the backfill of %s sets both days and fullHistory, pick one", obj.ObjectName)
+		}
+	})
+}
+
+func checkMappingCollisions(lint *linter) {
+	lint.eachReadObject(func(obj *openapi.IntegrationObject, path Path) {
+		// The first field that maps to a name, by lower-cased name
+		first := map[string]string{}
+
+		for _, list := range []struct {
+			key    string
+			fields *[]openapi.IntegrationField
+		}{
+			{"requiredFields", obj.RequiredFields},
+			{"optionalFields", obj.OptionalFields},
+		} {
+			if list.fields == nil {
+				continue
+			}
+
+			for idx, field := range *list.fields {
+				name := mappedName(field)
+				if name == "" {
+					continue
+				}
+
+				label := fmt.Sprintf("%s[%d]", list.key, idx)
+
+				key := strings.ToLower(name)
+				if prev, ok := first[key]; ok {
+					lint.report(path.Key(list.key).Index(idx),
+						"%s of %s maps to %s like %s, so one of them can't be reached", label, obj.ObjectName, name, prev)
+
+					continue
+				}
+
+				first[key] = label
+			}
+		}
+	})
+}
+
+// mappedName is the name under which a field ends up in the records: its mapping, or else
+// its own name.
+func mappedName(field openapi.IntegrationField) string {
+	existent, err := field.AsIntegrationFieldExistent()
+	if err == nil && existent.FieldName != "" {
+		if existent.MapToName != "" {
+			return existent.MapToName
+		}
+
+		return existent.FieldName
+	}
+
+	mapping, err := field.AsIntegrationFieldMapping()
+	if err == nil {
+		return mapping.MapToName
+	}
+
+	return ""
+}
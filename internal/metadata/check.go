@@ -0,0 +1,147 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/amp-labs/cli/openapi"
+)
+
+// UnknownField is a field of a read object that the provider doesn't have.
+type UnknownField struct {
+	Object string
+	Field  string
+
+	// Suggestion is the closest field the provider has, if any is close enough to be a typo.
+	Suggestion string
+}
+
+func (u UnknownField) String() string {
+	msg := fmt.Sprintf("%s has no field %s", u.Object, u.Field)
+	if u.Suggestion != "" {
+		msg += ", did you mean " + u.Suggestion + "?"
+	}
+
+	return msg
+}
+
+// CheckFields returns the required and optional fields of the integration's read objects
+// that the source doesn't know. Field mappings name no provider field, so they are not
+// checked, and neither are objects the source has no metadata for.
+func CheckFields(ctx context.Context, source Source, integ *openapi.Integration) ([]UnknownField, error) {
+	if integ.Read == nil || integ.Read.Objects == nil {
+		return nil, nil
+	}
+
+	var unknown []UnknownField
+
+	for _, obj := range *integ.Read.Objects {
+		fields, err := source.Fields(ctx, obj.ObjectName)
+		if errors.Is(err, ErrUnknownObject) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		for _, list := range []*[]openapi.IntegrationField{obj.RequiredFields, obj.OptionalFields} {
+			if list == nil {
+				continue
+			}
+
+			for _, field := range *list {
+				existent, err := field.AsIntegrationFieldExistent()
+				if err != nil || existent.FieldName == "" || hasField(fields, existent.FieldName) {
+					continue
+				}
+
+				unknown = append(unknown, UnknownField{
+					Object:     obj.ObjectName,
+					Field:      existent.FieldName,
+					Suggestion: Suggest(fields, existent.FieldName),
+				})
+			}
+		}
+	}
+
+	return unknown, nil
+}
+
+// CheckField returns the field of the object if the source doesn't know it, or nil if it
+// does, or has no metadata for the object.
+func CheckField(ctx context.Context, source Source, objectName, fieldName string) (*UnknownField, error) {
+	fields, err := source.Fields(ctx, objectName)
+	if errors.Is(err, ErrUnknownObject) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if hasField(fields, fieldName) {
+		return nil, nil
+	}
+
+	return &UnknownField{Object: objectName, Field: fieldName, Suggestion: Suggest(fields, fieldName)}, nil
+}
+
+// Suggest returns the field whose name is closest to the given one, if it is close
+// enough to be a typo, or an empty string.
+func Suggest(fields []openapi.FieldMetadata, name string) string {
+	// Allow a typo every four letters, but at least one
+	best, bestDistance := "", max(1, len(name)/4) //nolint:mnd
+
+	for _, field := range fields {
+		distance := editDistance(strings.ToLower(name), strings.ToLower(field.FieldName))
+		if distance <= bestDistance && (best == "" || distance < bestDistance) {
+			best, bestDistance = field.FieldName, distance
+		}
+	}
+
+	return best
+}
+
+func hasField(fields []openapi.FieldMetadata, name string) bool {
+	for _, field := range fields {
+		if strings.EqualFold(field.FieldName, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// editDistance is the number of letters to insert, delete, replace or swap with the next one
+// to turn a into b (the optimal string alignment distance).
+func editDistance(a, b string) int {
+	dist := make([][]int, len(a)+1)
+	for i := range dist {
+		dist[i] = make([]int, len(b)+1)
+		dist[i][0] = i
+	}
+
+	for j := range dist[0] {
+		dist[0][j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			dist[i][j] = min(dist[i-1][j]+1, dist[i][j-1]+1, dist[i-1][j-1]+cost)
+
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				dist[i][j] = min(dist[i][j], dist[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return dist[len(a)][len(b)]
+}
@@ -0,0 +1,121 @@
+// Package metadata looks up the fields of provider objects, so that manifests can be
+// written by picking fields rather than typing their names from memory.
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/amp-labs/cli/openapi"
+	"github.com/amp-labs/cli/request"
+)
+
+var ErrUnknownObject = errors.New("no metadata for the object")
+
+// Source returns the fields of the objects of one provider.
+type Source interface {
+	// Fields returns the fields of the object, sorted by name.
+	Fields(ctx context.Context, objectName string) ([]openapi.FieldMetadata, error)
+}
+
+// File is a source backed by a JSON file, which maps object names to the object
+// metadata returned by the Ampersand API:
+//
+//	{"Account": {"displayName": "Account", "fields": {"Name": {"fieldName": "Name", ...}}}}
+type File struct {
+	objects map[string]request.ObjectMetadata
+}
+
+// ReadFile loads a metadata file.
+func ReadFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metadata file: %w", err)
+	}
+
+	var objects map[string]request.ObjectMetadata
+
+	err = json.Unmarshal(data, &objects)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse metadata file %s: %w", path, err)
+	}
+
+	return &File{objects: objects}, nil
+}
+
+func (f *File) Fields(_ context.Context, objectName string) ([]openapi.FieldMetadata, error) {
+	if obj, ok := f.objects[objectName]; ok {
+		return sortedFields(&obj), nil
+	}
+
+	for name, obj := range f.objects {
+		if strings.EqualFold(name, objectName) {
+			return sortedFields(&obj), nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w %s", ErrUnknownObject, objectName)
+}
+
+// API is a source backed by the Ampersand API, which reads the metadata through the
+// connection of a group. Objects are only fetched once.
+type API struct {
+	client   *request.APIClient
+	provider string
+	groupRef string
+	objects  map[string][]openapi.FieldMetadata
+}
+
+// NewAPI returns a source for the provider, that reads the metadata through the
+// connection of the group.
+func NewAPI(client *request.APIClient, provider, groupRef string) *API {
+	return &API{
+		client:   client,
+		provider: provider,
+		groupRef: groupRef,
+		objects:  map[string][]openapi.FieldMetadata{},
+	}
+}
+
+func (a *API) Fields(ctx context.Context, objectName string) ([]openapi.FieldMetadata, error) {
+	if fields, ok := a.objects[objectName]; ok {
+		return fields, nil
+	}
+
+	obj, err := a.client.GetObjectMetadata(ctx, a.provider, objectName, a.groupRef)
+	if errors.Is(err, request.ErrNotFound) {
+		return nil, fmt.Errorf("%w %s: %w", ErrUnknownObject, objectName, err)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the metadata of %s: %w", objectName, err)
+	}
+
+	fields := sortedFields(obj)
+	a.objects[objectName] = fields
+
+	return fields, nil
+}
+
+func sortedFields(obj *request.ObjectMetadata) []openapi.FieldMetadata {
+	fields := make([]openapi.FieldMetadata, 0, len(obj.Fields))
+
+	for name, field := range obj.Fields {
+		if field.FieldName == "" {
+			field.FieldName = name
+		}
+
+		fields = append(fields, field)
+	}
+
+	slices.SortFunc(fields, func(a, b openapi.FieldMetadata) int {
+		return strings.Compare(a.FieldName, b.FieldName)
+	})
+
+	return fields
+}
@@ -0,0 +1,190 @@
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/amp-labs/cli/openapi"
+	"github.com/amp-labs/cli/request"
+)
+
+const accountJSON = `{
+	"displayName": "Account",
+	"fields": {
+		"Name": {"displayName": "Account Name", "valueType": "string"},
+		"Industry": {"fieldName": "Industry", "displayName": "Industry", "valueType": "singleSelect"},
+		"AnnualRevenue": {"fieldName": "AnnualRevenue", "displayName": "Annual Revenue", "valueType": "float"}
+	}
+}`
+
+func fieldNames(fields []openapi.FieldMetadata) []string {
+	names := make([]string, 0, len(fields))
+	for _, field := range fields {
+		names = append(names, field.FieldName)
+	}
+
+	return names
+}
+
+func TestFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "fields.json")
+
+	err := os.WriteFile(path, []byte(`{"Account": `+accountJSON+`}`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() returned %v", err)
+	}
+
+	fields, err := file.Fields(context.Background(), "account")
+	if err != nil {
+		t.Fatalf("Fields() returned %v", err)
+	}
+
+	// Sorted, and named after their key when the field name is left out
+	if got := fieldNames(fields); len(got) != 3 || got[0] != "AnnualRevenue" || got[2] != "Name" {
+		t.Errorf("Fields() = %v, want AnnualRevenue, Industry, Name", got)
+	}
+
+	_, err = file.Fields(context.Background(), "Lead")
+	if !errors.Is(err, ErrUnknownObject) {
+		t.Errorf("Fields() of a missing object returned %v, want ErrUnknownObject", err)
+	}
+}
+
+func TestAPI(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+
+		if r.URL.Path == "/projects/p/providers/salesforce/objects/Contact/metadata" {
+			w.WriteHeader(http.StatusBadGateway)
+
+			return
+		}
+
+		if r.URL.Path != "/projects/p/providers/salesforce/objects/Account/metadata" ||
+			r.URL.Query().Get("groupRef") != "acme" {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		var obj json.RawMessage = []byte(accountJSON)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(obj)
+	}))
+	defer srv.Close()
+
+	key := "key"
+	client := &request.APIClient{Root: srv.URL, ProjectId: "p", APIKey: &key, Client: request.NewRequestClient()}
+	source := NewAPI(client, "salesforce", "acme")
+
+	for range 2 {
+		fields, err := source.Fields(context.Background(), "Account")
+		if err != nil {
+			t.Fatalf("Fields() returned %v", err)
+		}
+
+		if len(fields) != 3 {
+			t.Errorf("Fields() returned %d fields, want 3", len(fields))
+		}
+	}
+
+	if calls.Load() != 1 {
+		t.Errorf("the API was called %d times, want once", calls.Load())
+	}
+
+	_, err := source.Fields(context.Background(), "Lead")
+	if !errors.Is(err, ErrUnknownObject) {
+		t.Errorf("Fields() of a missing object returned %v, want ErrUnknownObject", err)
+	}
+
+	// Failures of the API aren't mistaken for objects without metadata
+	_, err = source.Fields(context.Background(), "Contact")
+	if err == nil || errors.Is(err, ErrUnknownObject) {
+		t.Errorf("Fields() when the API fails returned %v, want an error other than ErrUnknownObject", err)
+	}
+}
+
+func TestCheckFields(t *testing.T) {
+	t.Parallel()
+
+	var obj request.ObjectMetadata
+
+	err := json.Unmarshal([]byte(accountJSON), &obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	source := &File{objects: map[string]request.ObjectMetadata{"Account": obj}}
+
+	field := func(name string) openapi.IntegrationField {
+		var field openapi.IntegrationField
+
+		err := field.FromIntegrationFieldExistent(openapi.IntegrationFieldExistent{FieldName: name})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return field
+	}
+
+	required := []openapi.IntegrationField{field("Nmae"), field("Industy")}
+	optional := []openapi.IntegrationField{field("annualrevenue"), field("Website")}
+	unknownObj := []openapi.IntegrationField{field("Anything")}
+
+	integ := &openapi.Integration{Read: &openapi.IntegrationRead{Objects: &[]openapi.IntegrationObject{
+		{ObjectName: "Account", RequiredFields: &required, OptionalFields: &optional},
+		{ObjectName: "Lead", RequiredFields: &unknownObj},
+	}}}
+
+	unknown, err := CheckFields(context.Background(), source, integ)
+	if err != nil {
+		t.Fatalf("CheckFields() returned %v", err)
+	}
+
+	want := []string{
+		"Account has no field Nmae, did you mean Name?",
+		"Account has no field Industy, did you mean Industry?",
+		"Account has no field Website",
+	}
+
+	if len(unknown) != len(want) {
+		t.Fatalf("CheckFields() = %v, want %v", unknown, want)
+	}
+
+	for idx, field := range unknown {
+		if field.String() != want[idx] {
+			t.Errorf("CheckFields()[%d] = %q, want %q", idx, field.String(), want[idx])
+		}
+	}
+
+	missing, err := CheckField(context.Background(), source, "Account", "Industy")
+	if err != nil || missing == nil || missing.Suggestion != "Industry" {
+		t.Errorf("CheckField() = %v, %v, want Industy to be unknown", missing, err)
+	}
+
+	for _, obj := range []string{"Account", "Lead"} {
+		missing, err = CheckField(context.Background(), source, obj, "industry")
+		if err != nil || missing != nil {
+			t.Errorf("CheckField() of %s = %v, %v, want nothing missing", obj, missing, err)
+		}
+	}
+}
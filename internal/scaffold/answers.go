@@ -0,0 +1,559 @@
+// Package scaffold builds Ampersand manifests from the answers to the questions asked by
+// `amp init`, whether they come from the interactive prompts, flags or an answers file,
+// and adds to existing manifests for `amp add`.
+package scaffold
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/amp-labs/cli/openapi"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// SpecVersion is the manifest version written by `amp init`.
+	SpecVersion = "1.0.0"
+
+	// DefaultDisplayName is used when no display name is given for an integration.
+	DefaultDisplayName = "my integration"
+)
+
+var (
+	ErrMissingName     = errors.New("the integration needs a name")
+	ErrMissingProvider = errors.New("the integration needs a provider")
+	ErrUnsupported     = errors.New("not supported by the provider")
+	ErrProviderChanged = errors.New("the answers are for a different provider")
+	ErrNotRead         = errors.New("inheritFieldsAndMapping needs the object to be read as well")
+	ErrNoEvents        = errors.New("a subscribe object needs at least one event")
+	ErrWatchFieldsAuto = errors.New("watchFieldsAuto must be 'all' or 'selected'")
+	ErrNoObjects       = errors.New("watchSchema needs objects that are read or subscribed to")
+	ErrUnknownModule   = errors.New("unknown module")
+)
+
+// Answers describes an integration the way `amp init` asks about it.
+type Answers struct {
+	Name        string            `json:"name"`
+	DisplayName string            `json:"displayName,omitempty"`
+	Provider    string            `json:"provider"`
+	Module      string            `json:"module,omitempty"`
+	Read        []ReadObject      `json:"read,omitempty"`
+	Write       []WriteObject     `json:"write,omitempty"`
+	Proxy       bool              `json:"proxy,omitempty"`
+	Subscribe   []SubscribeObject `json:"subscribe,omitempty"`
+	WatchSchema *WatchSchema      `json:"watchSchema,omitempty"`
+}
+
+// ReadObject describes an object to read.
+type ReadObject struct {
+	ObjectName     string    `json:"objectName"`
+	Destination    string    `json:"destination,omitempty"`
+	Schedule       string    `json:"schedule,omitempty"`
+	Backfill       *Backfill `json:"backfill,omitempty"`
+	RequiredFields []Field   `json:"requiredFields,omitempty"`
+	OptionalFields []Field   `json:"optionalFields,omitempty"`
+	// AllowUserFields lets users add their own optional fields (optionalFieldsAuto: all).
+	AllowUserFields bool `json:"allowUserFields,omitempty"`
+}
+
+// Backfill is how much history to read the first time, either all of it or some days.
+type Backfill struct {
+	FullHistory bool `json:"fullHistory,omitempty"`
+	Days        int  `json:"days,omitempty"`
+}
+
+// WriteObject describes an object to write.
+type WriteObject struct {
+	ObjectName string `json:"objectName"`
+}
+
+// SubscribeObject describes an object whose changes are pushed as they happen.
+type SubscribeObject struct {
+	ObjectName  string `json:"objectName"`
+	Destination string `json:"destination,omitempty"`
+	// InheritFieldsAndMapping reuses the fields and mapping of the read object of the same name.
+	InheritFieldsAndMapping bool `json:"inheritFieldsAndMapping,omitempty"`
+	Create                  bool `json:"create,omitempty"`
+	Update                  bool `json:"update,omitempty"`
+	Delete                  bool `json:"delete,omitempty"`
+	AssociationChange       bool `json:"associationChange,omitempty"`
+	// IncludeFullRecords adds the full records to association change events.
+	IncludeFullRecords bool `json:"includeFullRecords,omitempty"`
+	// WatchFields are the fields whose changes always trigger an update event.
+	WatchFields []string `json:"watchFields,omitempty"`
+	// WatchFieldsAuto is "all" to watch every field, or "selected" to watch the fields users select.
+	WatchFieldsAuto string `json:"watchFieldsAuto,omitempty"`
+}
+
+// WatchSchema describes the notifications sent when the provider's fields change.
+type WatchSchema struct {
+	Destination  string `json:"destination,omitempty"`
+	Schedule     string `json:"schedule,omitempty"`
+	FieldCreated bool   `json:"fieldCreated,omitempty"`
+	FieldDeleted bool   `json:"fieldDeleted,omitempty"`
+	FieldChanged bool   `json:"fieldChanged,omitempty"`
+}
+
+// Field is a field of a read object. It is written as a plain field name unless users may
+// map it to a field of their choosing, which Mapped is set for.
+type Field struct {
+	FieldName   string  `json:"fieldName"`
+	Mapped      bool    `json:"mapped,omitempty"`
+	DisplayName string  `json:"displayName,omitempty"`
+	Default     *string `json:"default,omitempty"`
+	Prompt      *string `json:"prompt,omitempty"`
+}
+
+// UnmarshalJSON accepts a plain field name as shorthand for an unmapped field.
+func (f *Field) UnmarshalJSON(data []byte) error {
+	var name string
+	if json.Unmarshal(data, &name) == nil {
+		*f = Field{FieldName: name}
+
+		return nil
+	}
+
+	type plain Field
+
+	return json.Unmarshal(data, (*plain)(f))
+}
+
+// ReadAnswers loads an answers file, in YAML or JSON.
+func ReadAnswers(path string) (*Answers, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	answers := &Answers{}
+
+	err = yaml.UnmarshalStrict(data, answers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse answers file %s: %w", path, err)
+	}
+
+	return answers, nil
+}
+
+// Integration builds the integration described by the answers. The provider is the catalog
+// entry for Answers.Provider, and is used to check that it supports what is asked for.
+func (a *Answers) Integration(provider *openapi.ProviderInfo) (openapi.Integration, error) {
+	if a.Name == "" {
+		return openapi.Integration{}, ErrMissingName
+	}
+
+	if a.Provider == "" {
+		return openapi.Integration{}, ErrMissingProvider
+	}
+
+	if provider.Name != a.Provider {
+		return openapi.Integration{}, fmt.Errorf("%w: %s, not %s", ErrProviderChanged, a.Provider, provider.Name)
+	}
+
+	module, err := ModuleProvider(provider, a.Module)
+	if err != nil {
+		return openapi.Integration{}, err
+	}
+
+	err = a.checkSupport(module)
+	if err != nil {
+		return openapi.Integration{}, err
+	}
+
+	integ := openapi.Integration{
+		Name:        a.Name,
+		DisplayName: a.DisplayName,
+		Provider:    a.Provider,
+		Module:      a.Module,
+	}
+
+	// Which module is meant only needs spelling out when there is a choice
+	if integ.Module == "" && provider.Modules != nil && len(*provider.Modules) > 1 {
+		integ.Module = provider.DefaultModule
+	}
+
+	if integ.DisplayName == "" {
+		integ.DisplayName = DefaultDisplayName
+	}
+
+	if len(a.Read) > 0 {
+		objects := make([]openapi.IntegrationObject, 0, len(a.Read))
+
+		for _, obj := range a.Read {
+			built, err := obj.integrationObject()
+			if err != nil {
+				return openapi.Integration{}, err
+			}
+
+			objects = append(objects, built)
+		}
+
+		integ.Read = &openapi.IntegrationRead{Objects: &objects}
+	}
+
+	if len(a.Write) > 0 {
+		objects := make([]openapi.IntegrationWriteObject, 0, len(a.Write))
+		for _, obj := range a.Write {
+			objects = append(objects, openapi.IntegrationWriteObject{ObjectName: obj.ObjectName})
+		}
+
+		integ.Write = &openapi.IntegrationWrite{Objects: &objects}
+	}
+
+	if a.Proxy {
+		enabled := true
+		integ.Proxy = &openapi.IntegrationProxy{Enabled: &enabled}
+	}
+
+	if len(a.Subscribe) > 0 {
+		objects := make([]openapi.IntegrationSubscribeObject, 0, len(a.Subscribe))
+
+		for _, obj := range a.Subscribe {
+			built, err := obj.subscribeObject(a)
+			if err != nil {
+				return openapi.Integration{}, err
+			}
+
+			objects = append(objects, built)
+		}
+
+		integ.Subscribe = &openapi.IntegrationSubscribe{Objects: &objects}
+	}
+
+	if a.WatchSchema != nil {
+		if len(a.Read) == 0 && len(a.Subscribe) == 0 {
+			return openapi.Integration{}, ErrNoObjects
+		}
+
+		integ.WatchSchema = a.WatchSchema.watchSchema()
+	}
+
+	return integ, nil
+}
+
+// reads tells whether the answers read the object.
+func (a *Answers) reads(objectName string) bool {
+	for _, obj := range a.Read {
+		if obj.ObjectName == objectName {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (a *Answers) checkSupport(provider *openapi.ProviderInfo) error {
+	name := provider.DisplayName
+	if name == "" {
+		name = provider.Name
+	}
+
+	switch {
+	case len(a.Read) > 0 && !provider.Support.Read:
+		return fmt.Errorf("read is %w %s", ErrUnsupported, name)
+	case len(a.Write) > 0 && !provider.Support.Write:
+		return fmt.Errorf("write is %w %s", ErrUnsupported, name)
+	case a.Proxy && !provider.Support.Proxy:
+		return fmt.Errorf("proxy is %w %s", ErrUnsupported, name)
+	case len(a.Subscribe) > 0 && !provider.Support.Subscribe:
+		return fmt.Errorf("subscribe is %w %s", ErrUnsupported, name)
+	}
+
+	events := SupportedEvents(provider.Support)
+
+	for _, obj := range a.Subscribe {
+		switch {
+		case obj.Create && !events.Create:
+			return fmt.Errorf("subscribing to create events is %w %s", ErrUnsupported, name)
+		case obj.Update && !events.Update:
+			return fmt.Errorf("subscribing to update events is %w %s", ErrUnsupported, name)
+		case obj.Delete && !events.Delete:
+			return fmt.Errorf("subscribing to delete events is %w %s", ErrUnsupported, name)
+		}
+	}
+
+	return nil
+}
+
+// ModuleProvider returns the provider as integrations that use the module see it, with the
+// module's support matrix and display name. An empty module is the provider's default one.
+func ModuleProvider(provider *openapi.ProviderInfo, module string) (*openapi.ProviderInfo, error) {
+	var modules openapi.Modules
+	if provider.Modules != nil {
+		modules = *provider.Modules
+	}
+
+	name := module
+	if name == "" {
+		name = provider.DefaultModule
+	}
+
+	info, ok := modules[name]
+	if !ok {
+		if module == "" {
+			return provider, nil
+		}
+
+		return nil, fmt.Errorf("%w %q for %s (available: %s)", ErrUnknownModule, module, provider.Name,
+			strings.Join(ModuleNames(provider), ", "))
+	}
+
+	moduleProvider := *provider
+	moduleProvider.Support = info.Support
+
+	if info.DisplayName != "" {
+		moduleProvider.DisplayName = info.DisplayName
+	}
+
+	return &moduleProvider, nil
+}
+
+// ModuleNames returns the names of the provider's modules, the default one first.
+func ModuleNames(provider *openapi.ProviderInfo) []string {
+	if provider.Modules == nil {
+		return nil
+	}
+
+	names := make([]string, 0, len(*provider.Modules))
+	for name := range *provider.Modules {
+		names = append(names, name)
+	}
+
+	slices.SortFunc(names, func(a, b string) int {
+		switch {
+		case a == provider.DefaultModule:
+			return -1
+		case b == provider.DefaultModule:
+			return 1
+		default:
+			return strings.Compare(a, b)
+		}
+	})
+
+	return names
+}
+
+// Events tells which record events can be subscribed to.
+type Events struct {
+	Create bool
+	Update bool
+	Delete bool
+}
+
+// SupportedEvents returns the events a provider lets integrations subscribe to. Providers
+// that support subscribe without detailing it are taken to support every event.
+func SupportedEvents(support openapi.Support) Events {
+	if !support.Subscribe {
+		return Events{}
+	}
+
+	events := Events{Create: true, Update: true, Delete: true}
+
+	if details := support.SubscribeSupport; details != nil {
+		events.Create = details.Create == nil || *details.Create
+		events.Update = details.Update == nil || *details.Update
+		events.Delete = details.Delete == nil || *details.Delete
+	}
+
+	return events
+}
+
+func (r *ReadObject) integrationObject() (openapi.IntegrationObject, error) {
+	obj := openapi.IntegrationObject{
+		ObjectName:  r.ObjectName,
+		Destination: r.Destination,
+		Schedule:    r.Schedule,
+	}
+
+	if r.Backfill != nil {
+		obj.Backfill = r.Backfill.backfill()
+	}
+
+	required, err := integrationFields(r.RequiredFields)
+	if err != nil {
+		return obj, err
+	}
+
+	optional, err := integrationFields(r.OptionalFields)
+	if err != nil {
+		return obj, err
+	}
+
+	obj.RequiredFields = required
+	obj.OptionalFields = optional
+
+	if r.AllowUserFields {
+		auto := openapi.OptionalFieldsAutoOptionAll
+		obj.OptionalFieldsAuto = &auto
+	}
+
+	return obj, nil
+}
+
+func (s *SubscribeObject) subscribeObject(answers *Answers) (openapi.IntegrationSubscribeObject, error) {
+	obj := openapi.IntegrationSubscribeObject{
+		ObjectName:              s.ObjectName,
+		Destination:             s.Destination,
+		InheritFieldsAndMapping: s.InheritFieldsAndMapping,
+	}
+
+	if s.InheritFieldsAndMapping && !answers.reads(s.ObjectName) {
+		return obj, fmt.Errorf("%w: %s", ErrNotRead, s.ObjectName)
+	}
+
+	if !s.Create && !s.Update && !s.Delete && !s.AssociationChange {
+		return obj, fmt.Errorf("%w: %s", ErrNoEvents, s.ObjectName)
+	}
+
+	if s.Create {
+		enabled := openapi.CreateEventEnabledAlways
+		obj.CreateEvent = &openapi.CreateEvent{Enabled: &enabled}
+	}
+
+	if s.Update {
+		enabled := openapi.UpdateEventEnabledAlways
+		obj.UpdateEvent = &openapi.UpdateEvent{Enabled: &enabled}
+
+		if len(s.WatchFields) > 0 {
+			fields := slices.Clone(s.WatchFields)
+			obj.UpdateEvent.RequiredWatchFields = &fields
+		}
+
+		if s.WatchFieldsAuto != "" {
+			auto := openapi.UpdateEventWatchFieldsAuto(s.WatchFieldsAuto)
+			if !auto.Valid() {
+				return obj, fmt.Errorf("%w, not %q", ErrWatchFieldsAuto, s.WatchFieldsAuto)
+			}
+
+			obj.UpdateEvent.WatchFieldsAuto = &auto
+		}
+	}
+
+	if s.Delete {
+		enabled := openapi.DeleteEventEnabledAlways
+		obj.DeleteEvent = &openapi.DeleteEvent{Enabled: &enabled}
+	}
+
+	if s.AssociationChange {
+		enabled := openapi.AssociationChangeEventEnabledAlways
+		obj.AssociationChangeEvent = &openapi.AssociationChangeEvent{Enabled: &enabled}
+
+		if s.IncludeFullRecords {
+			full := true
+			obj.AssociationChangeEvent.IncludeFullRecords = &full
+		}
+	}
+
+	return obj, nil
+}
+
+func (w *WatchSchema) watchSchema() *openapi.WatchSchema {
+	schema := &openapi.WatchSchema{
+		Destination: w.Destination,
+		Schedule:    w.Schedule,
+	}
+
+	if w.FieldCreated {
+		schema.AllObjects.FieldCreated = &openapi.FieldCreatedEvent{Enabled: openapi.FieldCreatedEventEnabledAlways}
+	}
+
+	if w.FieldDeleted {
+		schema.AllObjects.FieldDeleted = &openapi.FieldDeletedEvent{Enabled: openapi.FieldDeletedEventEnabledAlways}
+	}
+
+	if w.FieldChanged {
+		schema.AllObjects.FieldChanged = &openapi.FieldChangedEvent{Enabled: openapi.FieldChangedEventEnabledAlways}
+	}
+
+	return schema
+}
+
+func (b *Backfill) backfill() *openapi.Backfill {
+	if b.FullHistory {
+		full := true
+
+		return &openapi.Backfill{DefaultPeriod: openapi.DefaultPeriod{FullHistory: &full}}
+	}
+
+	if b.Days > 0 {
+		days := b.Days
+
+		return &openapi.Backfill{DefaultPeriod: openapi.DefaultPeriod{Days: &days}}
+	}
+
+	return nil
+}
+
+func integrationFields(fields []Field) (*[]openapi.IntegrationField, error) {
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	built := make([]openapi.IntegrationField, 0, len(fields))
+
+	for _, field := range fields {
+		integField, err := field.IntegrationField()
+		if err != nil {
+			return nil, err
+		}
+
+		built = append(built, *integField)
+	}
+
+	return &built, nil
+}
+
+// IntegrationField builds the manifest entry for the field.
+func (f *Field) IntegrationField() (*openapi.IntegrationField, error) {
+	field := &openapi.IntegrationField{}
+
+	if !f.Mapped {
+		err := field.FromIntegrationFieldExistent(openapi.IntegrationFieldExistent{FieldName: f.FieldName})
+
+		return field, err
+	}
+
+	// The display name is always written, even if empty, as the prompts always did
+	displayName := f.DisplayName
+	mapping := openapi.IntegrationFieldMapping{
+		MapToName:        f.FieldName,
+		MapToDisplayName: &displayName,
+		Default:          f.Default,
+		Prompt:           f.Prompt,
+	}
+
+	err := field.FromIntegrationFieldMapping(mapping)
+
+	return field, err
+}
+
+// FieldName returns the name a field of a read object goes by: the provider's name of the
+// field, or for field mappings, the name users map a field to.
+func FieldName(field openapi.IntegrationField) string {
+	existent, err := field.AsIntegrationFieldExistent()
+	if err == nil && existent.FieldName != "" {
+		return existent.FieldName
+	}
+
+	mapping, err := field.AsIntegrationFieldMapping()
+	if err == nil {
+		return mapping.MapToName
+	}
+
+	return ""
+}
+
+// Manifest wraps integrations into a manifest.
+func Manifest(integrations ...openapi.Integration) *openapi.Manifest {
+	// TODO: Add an endpoint to the API to fetch the latest spec version.
+	// If the spec version from the server is newer, we want to block
+	// the user from running this command until they upgrade.
+	return &openapi.Manifest{
+		SpecVersion:  SpecVersion,
+		Integrations: integrations,
+	}
+}
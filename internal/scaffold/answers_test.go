@@ -0,0 +1,299 @@
+package scaffold
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/amp-labs/cli/openapi"
+	"sigs.k8s.io/yaml"
+)
+
+func testProvider() *openapi.ProviderInfo {
+	return &openapi.ProviderInfo{
+		Name:        "salesforce",
+		DisplayName: "Salesforce",
+		Support:     openapi.Support{Read: true, Write: true, Proxy: true},
+	}
+}
+
+func TestReadAnswers(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "answers.yaml")
+
+	err := os.WriteFile(path, []byte(`
+name: x
+provider: salesforce
+read:
+  - objectName: Account
+    destination: myWebhook
+    schedule: "*/10 * * * *"
+    backfill: {days: 30}
+    requiredFields: [Id]
+    optionalFields:
+      - {fieldName: AnnualRevenue, mapped: true, displayName: Revenue, default: "0"}
+      - {fieldName: Rating, mapped: true}
+    allowUserFields: true
+write:
+  - objectName: Lead
+proxy: true
+`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	answers, err := ReadAnswers(path)
+	if err != nil {
+		t.Fatalf("ReadAnswers() returned %v", err)
+	}
+
+	integ, err := answers.Integration(testProvider())
+	if err != nil {
+		t.Fatalf("Integration() returned %v", err)
+	}
+
+	got, err := yaml.Marshal(Manifest(integ))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `integrations:
+- displayName: my integration
+  name: x
+  provider: salesforce
+  proxy:
+    enabled: true
+  read:
+    objects:
+    - backfill:
+        defaultPeriod:
+          days: 30
+      destination: myWebhook
+      objectName: Account
+      optionalFields:
+      - default: "0"
+        mapToDisplayName: Revenue
+        mapToName: AnnualRevenue
+      - mapToDisplayName: ""
+        mapToName: Rating
+      optionalFieldsAuto: all
+      requiredFields:
+      - fieldName: Id
+      schedule: '*/10 * * * *'
+  write:
+    objects:
+    - objectName: Lead
+specVersion: 1.0.0
+`
+	if string(got) != want {
+		t.Errorf("manifest =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestReadAnswersUnknownKey(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "answers.yaml")
+
+	err := os.WriteFile(path, []byte("name: x\nprovider: salesforce\nreads: []\n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = ReadAnswers(path)
+	if err == nil {
+		t.Error("ReadAnswers() accepted a misspelled key")
+	}
+}
+
+func TestIntegrationErrors(t *testing.T) {
+	t.Parallel()
+
+	proxyOnly := &openapi.ProviderInfo{Name: "notion", Support: openapi.Support{Proxy: true}}
+
+	tests := []struct {
+		name     string
+		answers  Answers
+		provider *openapi.ProviderInfo
+		wantErr  error
+	}{
+		{"no name", Answers{Provider: "salesforce"}, testProvider(), ErrMissingName},
+		{"no provider", Answers{Name: "x"}, testProvider(), ErrMissingProvider},
+		{"other provider", Answers{Name: "x", Provider: "hubspot"}, testProvider(), ErrProviderChanged},
+		{
+			"read", Answers{Name: "x", Provider: "notion", Read: []ReadObject{{ObjectName: "page"}}},
+			proxyOnly, ErrUnsupported,
+		},
+		{
+			"write", Answers{Name: "x", Provider: "notion", Write: []WriteObject{{ObjectName: "page"}}},
+			proxyOnly, ErrUnsupported,
+		},
+	}
+
+	for _, tt := range tests {
+		_, err := tt.answers.Integration(tt.provider)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: Integration() returned %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	t.Parallel()
+
+	provider := testProvider()
+	provider.Support.Subscribe = true
+
+	answers := Answers{
+		Name:     "x",
+		Provider: "salesforce",
+		Read:     []ReadObject{{ObjectName: "Account", Destination: "hook"}},
+		Subscribe: []SubscribeObject{{
+			ObjectName:              "Account",
+			Destination:             "hook",
+			InheritFieldsAndMapping: true,
+			Update:                  true,
+			WatchFields:             []string{"Name"},
+			WatchFieldsAuto:         "selected",
+			AssociationChange:       true,
+			IncludeFullRecords:      true,
+		}},
+		WatchSchema: &WatchSchema{Destination: "schema-hook", FieldCreated: true},
+	}
+
+	integ, err := answers.Integration(provider)
+	if err != nil {
+		t.Fatalf("Integration() returned %v", err)
+	}
+
+	got, err := yaml.Marshal(map[string]any{"subscribe": integ.Subscribe, "watchSchema": integ.WatchSchema})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `subscribe:
+  objects:
+  - associationChangeEvent:
+      enabled: always
+      includeFullRecords: true
+    destination: hook
+    inheritFieldsAndMapping: true
+    objectName: Account
+    updateEvent:
+      enabled: always
+      requiredWatchFields:
+      - Name
+      watchFieldsAuto: selected
+watchSchema:
+  allObjects:
+    fieldCreated:
+      enabled: always
+  destination: schema-hook
+`
+	if string(got) != want {
+		t.Errorf("subscribe =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestSubscribeErrors(t *testing.T) {
+	t.Parallel()
+
+	noDelete := false
+	provider := testProvider()
+	provider.Support.Subscribe = true
+	provider.Support.SubscribeSupport = &openapi.SubscribeSupport{Delete: &noDelete}
+
+	tests := []struct {
+		name    string
+		answers Answers
+		wantErr error
+	}{
+		{
+			"unsupported event",
+			Answers{Subscribe: []SubscribeObject{{ObjectName: "Account", Delete: true}}},
+			ErrUnsupported,
+		},
+		{
+			"inherit without read",
+			Answers{Subscribe: []SubscribeObject{{ObjectName: "Account", Create: true, InheritFieldsAndMapping: true}}},
+			ErrNotRead,
+		},
+		{"no events", Answers{Subscribe: []SubscribeObject{{ObjectName: "Account"}}}, ErrNoEvents},
+		{
+			"bad watchFieldsAuto",
+			Answers{Subscribe: []SubscribeObject{{ObjectName: "Account", Update: true, WatchFieldsAuto: "some"}}},
+			ErrWatchFieldsAuto,
+		},
+		{"watchSchema without objects", Answers{Proxy: true, WatchSchema: &WatchSchema{}}, ErrNoObjects},
+	}
+
+	for _, tt := range tests {
+		tt.answers.Name = "x"
+		tt.answers.Provider = "salesforce"
+
+		_, err := tt.answers.Integration(provider)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: Integration() returned %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+
+	events := SupportedEvents(provider.Support)
+	if !events.Create || !events.Update || events.Delete {
+		t.Errorf("SupportedEvents() = %+v, want create and update only", events)
+	}
+}
+
+func TestModules(t *testing.T) {
+	t.Parallel()
+
+	provider := testProvider()
+	provider.DefaultModule = "crm"
+	provider.Modules = &openapi.Modules{
+		"crm":    {DisplayName: "Salesforce CRM", Support: openapi.Support{Read: true, Write: true, Proxy: true}},
+		"pardot": {DisplayName: "Pardot", Support: openapi.Support{Read: true, Proxy: true}},
+	}
+
+	if names := ModuleNames(provider); len(names) != 2 || names[0] != "crm" || names[1] != "pardot" {
+		t.Errorf("ModuleNames() = %v, want the default module first", names)
+	}
+
+	module, err := ModuleProvider(provider, "pardot")
+	if err != nil {
+		t.Fatalf("ModuleProvider() returned %v", err)
+	}
+
+	if module.DisplayName != "Pardot" || module.Support.Write {
+		t.Errorf("ModuleProvider() = %+v, want the module's display name and support", module)
+	}
+
+	_, err = ModuleProvider(provider, "marketing")
+	if !errors.Is(err, ErrUnknownModule) {
+		t.Errorf("ModuleProvider() of an unknown module returned %v, want ErrUnknownModule", err)
+	}
+
+	write := Answers{Name: "x", Provider: "salesforce", Module: "pardot", Write: []WriteObject{{ObjectName: "Lead"}}}
+
+	_, err = write.Integration(provider)
+	if !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Integration() writing with a read-only module returned %v, want ErrUnsupported", err)
+	}
+
+	// The default module is written down when the provider has several
+	proxy := Answers{Name: "x", Provider: "salesforce", Proxy: true}
+
+	integ, err := proxy.Integration(provider)
+	if err != nil {
+		t.Fatalf("Integration() returned %v", err)
+	}
+
+	if integ.Module != "crm" {
+		t.Errorf("Integration().Module = %q, want the default module", integ.Module)
+	}
+
+	integ, err = proxy.Integration(testProvider())
+	if err != nil || integ.Module != "" {
+		t.Errorf("Integration() without modules = %q, %v, want no module", integ.Module, err)
+	}
+}
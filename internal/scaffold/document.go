@@ -0,0 +1,438 @@
+package scaffold
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/amp-labs/cli/openapi"
+	yamlv3 "go.yaml.in/yaml/v3"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	ErrNotFound       = errors.New("not found in the manifest")
+	ErrUnexpectedYAML = errors.New("unexpected manifest layout")
+)
+
+// Document is a manifest file that is edited in place. New entries are spliced into the
+// original text, so that comments, blank lines and quoting are kept as they are. Only
+// collections written in flow style ([a, b]) or left empty make it re-encode the whole file.
+type Document struct {
+	data []byte
+	node yamlv3.Node
+
+	// The layout of the file, which the new entries follow
+	indent     int
+	compactSeq bool
+}
+
+// ParseDocument parses a manifest file for editing.
+func ParseDocument(data []byte) (*Document, error) {
+	doc := &Document{data: data}
+
+	err := doc.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	doc.detectStyle()
+
+	return doc, nil
+}
+
+// Bytes returns the edited file.
+func (d *Document) Bytes() []byte {
+	return d.data
+}
+
+// AddIntegration appends an integration to the manifest.
+func (d *Document) AddIntegration(integ openapi.Integration) error {
+	return d.appendTo(d.root(), []string{"integrations"}, integ)
+}
+
+// AddReadObject appends an object to the read objects of an integration.
+func (d *Document) AddReadObject(integName string, obj openapi.IntegrationObject) error {
+	return d.addObject(integName, "read", obj)
+}
+
+// AddWriteObject appends an object to the write objects of an integration.
+func (d *Document) AddWriteObject(integName string, obj openapi.IntegrationWriteObject) error {
+	return d.addObject(integName, "write", obj)
+}
+
+// AddSubscribeObject appends an object to the subscribe objects of an integration.
+func (d *Document) AddSubscribeObject(integName string, obj openapi.IntegrationSubscribeObject) error {
+	return d.addObject(integName, "subscribe", obj)
+}
+
+// AddField appends a field to the required or optional fields of a read object.
+func (d *Document) AddField(integName, objName string, required bool, field openapi.IntegrationField) error {
+	integ, err := d.integration(integName)
+	if err != nil {
+		return err
+	}
+
+	objects := lookup(integ, "read", "objects")
+
+	obj := findItem(objects, "objectName", objName)
+	if obj == nil {
+		return fmt.Errorf("%w: read object %s of integration %s", ErrNotFound, objName, integName)
+	}
+
+	list := "optionalFields"
+	if required {
+		list = "requiredFields"
+	}
+
+	return d.appendTo(obj, []string{list}, field)
+}
+
+func (d *Document) addObject(integName, section string, obj any) error {
+	integ, err := d.integration(integName)
+	if err != nil {
+		return err
+	}
+
+	return d.appendTo(integ, []string{section, "objects"}, obj)
+}
+
+func (d *Document) parse() error {
+	d.node = yamlv3.Node{}
+
+	err := yamlv3.Unmarshal(d.data, &d.node)
+	if err != nil {
+		return fmt.Errorf("failed to parse yaml: %w", err)
+	}
+
+	if d.node.Kind != yamlv3.DocumentNode || len(d.node.Content) == 0 ||
+		d.node.Content[0].Kind != yamlv3.MappingNode {
+		return fmt.Errorf("%w: the manifest is not a mapping", ErrUnexpectedYAML)
+	}
+
+	return nil
+}
+
+func (d *Document) root() *yamlv3.Node {
+	return d.node.Content[0]
+}
+
+func (d *Document) integration(name string) (*yamlv3.Node, error) {
+	integ := findItem(lookup(d.root(), "integrations"), "name", name)
+	if integ == nil {
+		return nil, fmt.Errorf("%w: integration %s", ErrNotFound, name)
+	}
+
+	return integ, nil
+}
+
+// appendTo appends the value to the sequence found by following the keys from the mapping.
+// Missing keys are created.
+func (d *Document) appendTo(mapping *yamlv3.Node, keys []string, value any) error {
+	node := mapping
+
+	for idx, key := range keys {
+		child := lookup(node, key)
+
+		if child == nil || child.Tag == "!!null" {
+			// Nest the value under the keys that don't exist yet
+			var nested any = []any{value}
+			for i := len(keys) - 1; i > idx; i-- {
+				nested = map[string]any{keys[i]: nested}
+			}
+
+			if child != nil {
+				return d.rewrite(child, nested)
+			}
+
+			return d.insertPair(node, key, nested)
+		}
+
+		want := yamlv3.MappingNode
+		if idx == len(keys)-1 {
+			want = yamlv3.SequenceNode
+		}
+
+		if child.Kind != want {
+			return fmt.Errorf("%w: %s at line %d has an unexpected type", ErrUnexpectedYAML, key, child.Line)
+		}
+
+		node = child
+	}
+
+	return d.appendItem(node, value)
+}
+
+// appendItem adds the value after the last item of the sequence.
+func (d *Document) appendItem(seq *yamlv3.Node, value any) error {
+	if seq.Style&yamlv3.FlowStyle != 0 || len(seq.Content) == 0 {
+		item, err := toNode(value)
+		if err != nil {
+			return err
+		}
+
+		seq.Content = append(seq.Content, item)
+
+		return d.reencode()
+	}
+
+	last := seq.Content[len(seq.Content)-1]
+	dashCol := d.lineIndent(last.Line)
+
+	text, err := d.render([]any{value})
+	if err != nil {
+		return err
+	}
+
+	return d.splice(d.blockEnd(last.Line, dashCol, false), dashCol, text)
+}
+
+// insertPair adds the key and its value after the last pair of the mapping.
+func (d *Document) insertPair(mapping *yamlv3.Node, key string, value any) error {
+	if mapping.Style&yamlv3.FlowStyle != 0 || len(mapping.Content) == 0 {
+		keyNode := &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: key}
+
+		valueNode, err := toNode(value)
+		if err != nil {
+			return err
+		}
+
+		mapping.Content = append(mapping.Content, keyNode, valueNode)
+
+		return d.reencode()
+	}
+
+	keyCol := mapping.Content[0].Column - 1
+	lastKey := mapping.Content[len(mapping.Content)-2]
+
+	text, err := d.render(map[string]any{key: value})
+	if err != nil {
+		return err
+	}
+
+	return d.splice(d.blockEnd(lastKey.Line, keyCol, true), keyCol, text)
+}
+
+// rewrite replaces a node by the value and re-encodes the file.
+func (d *Document) rewrite(node *yamlv3.Node, value any) error {
+	replacement, err := toNode(value)
+	if err != nil {
+		return err
+	}
+
+	// Keep the comments of the node that is replaced
+	replacement.HeadComment = node.HeadComment
+	replacement.LineComment = node.LineComment
+	replacement.FootComment = node.FootComment
+	*node = *replacement
+
+	return d.reencode()
+}
+
+func (d *Document) reencode() error {
+	data, err := d.encode(&d.node)
+	if err != nil {
+		return err
+	}
+
+	d.data = data
+
+	return d.parse()
+}
+
+// splice inserts the text, indented by col spaces, before the line at index at.
+func (d *Document) splice(at, col int, text string) error {
+	lines := strings.SplitAfter(string(d.data), "\n")
+	if at > 0 && !strings.HasSuffix(lines[at-1], "\n") {
+		lines[at-1] += "\n"
+	}
+
+	indent := strings.Repeat(" ", col)
+
+	var inserted []string
+
+	for _, line := range strings.SplitAfter(strings.TrimSuffix(text, "\n"), "\n") {
+		inserted = append(inserted, indent+strings.TrimSuffix(line, "\n")+"\n")
+	}
+
+	lines = append(lines[:at], append(inserted, lines[at:]...)...)
+	d.data = []byte(strings.Join(lines, ""))
+
+	return d.parse()
+}
+
+// blockEnd returns the index of the line after the block that starts at the given line
+// (counted from 1) and goes on with the lines indented by more than col. Sequence items at
+// col belong to the block too when seqAtCol is set, as compact sequences are indented that way.
+// Blank lines after the block are left out of it, so they keep separating it from what follows.
+func (d *Document) blockEnd(line, col int, seqAtCol bool) int {
+	lines := strings.Split(string(d.data), "\n")
+	end := line
+
+	for idx := line; idx < len(lines); idx++ {
+		text := strings.TrimRight(lines[idx], "\r")
+		trimmed := strings.TrimLeft(text, " ")
+
+		if trimmed == "" {
+			continue
+		}
+
+		indent := len(text) - len(trimmed)
+		isItem := trimmed == "-" || strings.HasPrefix(trimmed, "- ")
+
+		if indent <= col && (!seqAtCol || indent != col || !isItem) {
+			break
+		}
+
+		end = idx + 1
+	}
+
+	return end
+}
+
+// lineIndent returns the number of spaces at the start of the line (counted from 1).
+func (d *Document) lineIndent(line int) int {
+	text := strings.Split(string(d.data), "\n")[line-1]
+
+	return len(text) - len(strings.TrimLeft(text, " "))
+}
+
+// detectStyle finds how the file indents mappings and sequences. Files without nested
+// collections get the layout written by `amp init`.
+func (d *Document) detectStyle() {
+	d.indent, d.compactSeq = 2, true //nolint:mnd
+
+	foundIndent, foundSeq := false, false
+
+	var walk func(node *yamlv3.Node)
+
+	walk = func(node *yamlv3.Node) {
+		if node.Style&yamlv3.FlowStyle != 0 || (foundIndent && foundSeq) {
+			return
+		}
+
+		if node.Kind == yamlv3.SequenceNode {
+			for _, item := range node.Content {
+				walk(item)
+			}
+
+			return
+		}
+
+		for idx := 0; node.Kind == yamlv3.MappingNode && idx+1 < len(node.Content); idx += 2 {
+			key, value := node.Content[idx], node.Content[idx+1]
+			block := value.Style&yamlv3.FlowStyle == 0 && len(value.Content) > 0
+
+			switch {
+			case block && value.Kind == yamlv3.MappingNode && !foundIndent:
+				d.indent, foundIndent = value.Column-key.Column, true
+			case block && value.Kind == yamlv3.SequenceNode && !foundSeq:
+				dashCol := d.lineIndent(value.Content[0].Line)
+				d.compactSeq, foundSeq = dashCol == key.Column-1, true
+
+				if !d.compactSeq && !foundIndent {
+					d.indent, foundIndent = dashCol-(key.Column-1), true
+				}
+			}
+
+			walk(value)
+		}
+	}
+
+	walk(d.root())
+}
+
+// render encodes the value with the layout of the file.
+func (d *Document) render(value any) (string, error) {
+	node, err := toNode(value)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := d.encode(node)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+func (d *Document) encode(node *yamlv3.Node) ([]byte, error) {
+	var buf bytes.Buffer
+
+	enc := yamlv3.NewEncoder(&buf)
+	enc.SetIndent(d.indent)
+
+	if d.compactSeq {
+		enc.CompactSeqIndent()
+	}
+
+	err := enc.Encode(node)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode yaml: %w", err)
+	}
+
+	err = enc.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode yaml: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// toNode converts a value to a YAML node. The value goes through JSON first, as the
+// manifest types only have JSON tags.
+func toNode(value any) (*yamlv3.Node, error) {
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal yaml: %w", err)
+	}
+
+	var node yamlv3.Node
+
+	err = yamlv3.Unmarshal(data, &node)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse yaml: %w", err)
+	}
+
+	return node.Content[0], nil
+}
+
+// lookup follows the keys from the mapping, and returns nil if one of them is missing.
+func lookup(node *yamlv3.Node, keys ...string) *yamlv3.Node {
+	for _, key := range keys {
+		if node == nil || node.Kind != yamlv3.MappingNode {
+			return nil
+		}
+
+		var found *yamlv3.Node
+
+		for idx := 0; idx+1 < len(node.Content); idx += 2 {
+			if node.Content[idx].Value == key {
+				found = node.Content[idx+1]
+
+				break
+			}
+		}
+
+		node = found
+	}
+
+	return node
+}
+
+// findItem returns the mapping in the sequence whose key has the given value.
+func findItem(seq *yamlv3.Node, key, value string) *yamlv3.Node {
+	if seq == nil || seq.Kind != yamlv3.SequenceNode {
+		return nil
+	}
+
+	for _, item := range seq.Content {
+		if found := lookup(item, key); found != nil && found.Value == value {
+			return item
+		}
+	}
+
+	return nil
+}
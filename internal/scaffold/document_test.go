@@ -0,0 +1,208 @@
+package scaffold
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/amp-labs/cli/openapi"
+)
+
+func existentField(t *testing.T, name string) openapi.IntegrationField {
+	t.Helper()
+
+	var field openapi.IntegrationField
+
+	err := field.FromIntegrationFieldExistent(openapi.IntegrationFieldExistent{FieldName: name})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return field
+}
+
+func TestDocumentKeepsFormatting(t *testing.T) {
+	t.Parallel()
+
+	doc, err := ParseDocument([]byte(`# Our integrations
+specVersion: 1.0.0
+
+integrations:
+# The main one
+- name: x   # keep this spacing
+  provider: salesforce
+  read:
+    objects:
+    - objectName: Account
+      destination: "hook"
+
+      # every ten minutes
+      schedule: '*/10 * * * *'
+
+- name: y
+  provider: hubspot
+  proxy:
+    enabled: true
+# end of integrations
+`))
+	if err != nil {
+		t.Fatalf("ParseDocument() returned %v", err)
+	}
+
+	steps := []error{
+		doc.AddReadObject("x", openapi.IntegrationObject{ObjectName: "Lead", Destination: "hook", Schedule: "@daily"}),
+		doc.AddWriteObject("x", openapi.IntegrationWriteObject{ObjectName: "Lead"}),
+		doc.AddField("x", "Account", true, existentField(t, "Id")),
+		doc.AddIntegration(openapi.Integration{Name: "z", Provider: "notion"}),
+	}
+
+	for _, err := range steps {
+		if err != nil {
+			t.Fatalf("editing returned %v", err)
+		}
+	}
+
+	want := `# Our integrations
+specVersion: 1.0.0
+
+integrations:
+# The main one
+- name: x   # keep this spacing
+  provider: salesforce
+  read:
+    objects:
+    - objectName: Account
+      destination: "hook"
+
+      # every ten minutes
+      schedule: '*/10 * * * *'
+      requiredFields:
+      - fieldName: Id
+    - destination: hook
+      objectName: Lead
+      schedule: '@daily'
+  write:
+    objects:
+    - objectName: Lead
+
+- name: y
+  provider: hubspot
+  proxy:
+    enabled: true
+- name: z
+  provider: notion
+# end of integrations
+`
+	if got := string(doc.Bytes()); got != want {
+		t.Errorf("document =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestDocumentFollowsIndentation(t *testing.T) {
+	t.Parallel()
+
+	doc, err := ParseDocument([]byte(`specVersion: 1.0.0
+integrations:
+    - name: x
+      provider: salesforce
+      subscribe:
+          objects:
+              - objectName: Account
+                destination: hook`))
+	if err != nil {
+		t.Fatalf("ParseDocument() returned %v", err)
+	}
+
+	err = doc.AddSubscribeObject("x", openapi.IntegrationSubscribeObject{ObjectName: "Lead", Destination: "hook"})
+	if err != nil {
+		t.Fatalf("AddSubscribeObject() returned %v", err)
+	}
+
+	want := `specVersion: 1.0.0
+integrations:
+    - name: x
+      provider: salesforce
+      subscribe:
+          objects:
+              - objectName: Account
+                destination: hook
+              - destination: hook
+                objectName: Lead
+`
+	if got := string(doc.Bytes()); got != want {
+		t.Errorf("document =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestDocumentFlowStyle(t *testing.T) {
+	t.Parallel()
+
+	doc, err := ParseDocument([]byte(`specVersion: 1.0.0
+integrations:
+- name: x
+  provider: salesforce
+  read:
+    objects:
+    - objectName: Account
+      destination: hook
+      requiredFields: [{fieldName: Id}]
+      optionalFields:
+`))
+	if err != nil {
+		t.Fatalf("ParseDocument() returned %v", err)
+	}
+
+	err = doc.AddField("x", "Account", true, existentField(t, "Name"))
+	if err != nil {
+		t.Fatalf("AddField() returned %v", err)
+	}
+
+	err = doc.AddField("x", "Account", false, existentField(t, "Industry"))
+	if err != nil {
+		t.Fatalf("AddField() returned %v", err)
+	}
+
+	want := `specVersion: 1.0.0
+integrations:
+- name: x
+  provider: salesforce
+  read:
+    objects:
+    - objectName: Account
+      destination: hook
+      requiredFields: [{fieldName: Id}, {fieldName: Name}]
+      optionalFields:
+      - fieldName: Industry
+`
+	if got := string(doc.Bytes()); got != want {
+		t.Errorf("document =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestDocumentErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := ParseDocument([]byte("- not a manifest\n"))
+	if !errors.Is(err, ErrUnexpectedYAML) {
+		t.Errorf("ParseDocument() of a sequence returned %v, want ErrUnexpectedYAML", err)
+	}
+
+	doc, err := ParseDocument([]byte("specVersion: 1.0.0\nintegrations:\n- name: x\n  provider: p\n  read: true\n"))
+	if err != nil {
+		t.Fatalf("ParseDocument() returned %v", err)
+	}
+
+	err = doc.AddWriteObject("nope", openapi.IntegrationWriteObject{ObjectName: "Lead"})
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("AddWriteObject() to a missing integration returned %v, want ErrNotFound", err)
+	}
+
+	err = doc.AddField("x", "Account", false, existentField(t, "Id"))
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("AddField() to a missing object returned %v, want ErrNotFound", err)
+	}
+
+	err = doc.AddReadObject("x", openapi.IntegrationObject{ObjectName: "Lead"})
+	if !errors.Is(err, ErrUnexpectedYAML) {
+		t.Errorf("AddReadObject() under a scalar returned %v, want ErrUnexpectedYAML", err)
+	}
+}
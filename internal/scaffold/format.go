@@ -0,0 +1,173 @@
+package scaffold
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/amp-labs/cli/openapi"
+	yamlv3 "go.yaml.in/yaml/v3"
+)
+
+// unionVariants are the types a union of the manifest can hold. The unions keep their value as
+// raw JSON, so their keys can't be found from their own type.
+var unionVariants = map[reflect.Type][]reflect.Type{ //nolint:gochecknoglobals
+	reflect.TypeFor[openapi.IntegrationField](): {
+		reflect.TypeFor[openapi.IntegrationFieldExistent](),
+		reflect.TypeFor[openapi.IntegrationFieldMapping](),
+	},
+}
+
+// Format rewrites a manifest in the canonical layout: keys in the order of the fields of
+// openapi.Manifest, collections in block style, mappings indented by two spaces and sequences
+// not indented, as written by `amp init`. Comments are kept with the keys they describe, and
+// the comment at the top of the file stays there. Keys the schema doesn't know go after the
+// known ones, in their original order.
+func Format(data []byte) ([]byte, error) {
+	doc := &Document{data: data, indent: 2, compactSeq: true} //nolint:mnd
+
+	err := doc.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	root := doc.root()
+	if len(root.Content) == 0 {
+		return doc.encode(&doc.node)
+	}
+
+	// The comment at the top belongs to the file, not to the key that happens to come first
+	header := root.Content[0].HeadComment
+	root.Content[0].HeadComment = ""
+
+	canonicalize(root, reflect.TypeFor[openapi.Manifest]())
+
+	root.Content[0].HeadComment = joinComments(header, root.Content[0].HeadComment)
+
+	return doc.encode(&doc.node)
+}
+
+// canonicalize puts the node in block style and its keys in the order of the fields of the type,
+// recursively. The type is nil for values the schema doesn't describe.
+func canonicalize(node *yamlv3.Node, typ reflect.Type) {
+	for typ != nil && typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	if node.Kind == yamlv3.MappingNode || node.Kind == yamlv3.SequenceNode {
+		node.Style &^= yamlv3.FlowStyle
+	}
+
+	switch node.Kind { //nolint:exhaustive
+	case yamlv3.SequenceNode:
+		var elem reflect.Type
+		if typ != nil && typ.Kind() == reflect.Slice {
+			elem = typ.Elem()
+		}
+
+		for _, item := range node.Content {
+			canonicalize(item, elem)
+		}
+	case yamlv3.MappingNode:
+		canonicalizeMapping(node, typ)
+	}
+}
+
+func canonicalizeMapping(node *yamlv3.Node, typ reflect.Type) {
+	var fields map[string]reflect.StructField
+
+	if typ != nil && typ.Kind() == reflect.Struct {
+		fields = jsonFields(unionVariant(node, typ))
+	}
+
+	type pair struct {
+		key, value *yamlv3.Node
+		order      int
+		field      reflect.Type
+	}
+
+	pairs := make([]pair, 0, len(node.Content)/2) //nolint:mnd
+
+	for idx := 0; idx+1 < len(node.Content); idx += 2 {
+		item := pair{key: node.Content[idx], value: node.Content[idx+1], order: len(node.Content)}
+
+		if field, ok := fields[item.key.Value]; ok {
+			item.order, item.field = field.Index[0], field.Type
+		} else if typ != nil && typ.Kind() == reflect.Map {
+			item.field = typ.Elem()
+		}
+
+		pairs = append(pairs, item)
+	}
+
+	slices.SortStableFunc(pairs, func(a, b pair) int {
+		return a.order - b.order
+	})
+
+	node.Content = node.Content[:0]
+
+	for _, item := range pairs {
+		canonicalize(item.value, item.field)
+		node.Content = append(node.Content, item.key, item.value)
+	}
+}
+
+// unionVariant returns the variant of a union whose fields include all the keys of the
+// mapping, or the type itself if it isn't a union.
+func unionVariant(node *yamlv3.Node, typ reflect.Type) reflect.Type {
+	variants, ok := unionVariants[typ]
+	if !ok {
+		return typ
+	}
+
+	for _, variant := range variants {
+		fields := jsonFields(variant)
+		known := true
+
+		for idx := 0; idx < len(node.Content); idx += 2 {
+			if _, ok := fields[node.Content[idx].Value]; !ok {
+				known = false
+
+				break
+			}
+		}
+
+		if known {
+			return variant
+		}
+	}
+
+	return nil
+}
+
+// jsonFields returns the fields of a struct by their JSON name.
+func jsonFields(typ reflect.Type) map[string]reflect.StructField {
+	if typ == nil {
+		return nil
+	}
+
+	fields := make(map[string]reflect.StructField, typ.NumField())
+
+	for idx := range typ.NumField() {
+		field := typ.Field(idx)
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields[name] = field
+		}
+	}
+
+	return fields
+}
+
+func joinComments(comments ...string) string {
+	var parts []string
+
+	for _, comment := range comments {
+		if comment != "" {
+			parts = append(parts, comment)
+		}
+	}
+
+	return strings.Join(parts, "\n\n")
+}
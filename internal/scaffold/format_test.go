@@ -0,0 +1,95 @@
+package scaffold
+
+import (
+	"testing"
+
+	"sigs.k8s.io/yaml"
+)
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+
+	input := `# Contacts of the CRM
+
+specVersion: 1.0.0 # the only one
+integrations:
+    -   provider: salesforce
+        name: x
+        # Read every ten minutes
+        read:
+            objects:
+                - schedule: "*/10 * * * *"
+                  objectName: Account
+                  destination: hook
+                  requiredFields: [{prompt: Pick one, mapToName: owner}, Id]
+                  custom: yes
+        proxy: {useModule: true, enabled: true}
+`
+
+	want := `# Contacts of the CRM
+
+integrations:
+- name: x
+  provider: salesforce
+  proxy:
+    enabled: true
+    useModule: true
+  # Read every ten minutes
+  read:
+    objects:
+    - destination: hook
+      objectName: Account
+      requiredFields:
+      - mapToName: owner
+        prompt: Pick one
+      - Id
+      schedule: "*/10 * * * *"
+      custom: yes
+specVersion: 1.0.0 # the only one
+`
+
+	got, err := Format([]byte(input))
+	if err != nil {
+		t.Fatalf("Format() returned %v", err)
+	}
+
+	if string(got) != want {
+		t.Errorf("Format() =\n%s\nwant\n%s", got, want)
+	}
+
+	again, err := Format(got)
+	if err != nil || string(again) != want {
+		t.Errorf("Format() of a formatted manifest =\n%s\n(%v), want it unchanged", again, err)
+	}
+}
+
+func TestFormatKeepsInitLayout(t *testing.T) {
+	t.Parallel()
+
+	answers := &Answers{
+		Name:     "x",
+		Provider: "salesforce",
+		Read:     []ReadObject{{ObjectName: "Account", Destination: "hook", Schedule: "*/10 * * * *"}},
+		Write:    []WriteObject{{ObjectName: "Lead"}},
+		Proxy:    true,
+	}
+
+	integ, err := answers.Integration(testProvider())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := yaml.Marshal(Manifest(integ))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Format(data)
+	if err != nil {
+		t.Fatalf("Format() returned %v", err)
+	}
+
+	if string(got) != string(data) {
+		t.Errorf("Format() changed the manifest of amp init:\n%s\nto\n%s", data, got)
+	}
+}
@@ -0,0 +1,363 @@
+package scaffold
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/alexkappa/mustache"
+	"github.com/amp-labs/cli/openapi"
+	yamlv3 "go.yaml.in/yaml/v3"
+	"sigs.k8s.io/yaml"
+)
+
+// templateExt is the extension of template files, which are manifests written in mustache.
+const templateExt = ".yaml"
+
+//go:embed templates/*.yaml
+var builtinTemplates embed.FS
+
+var ( //nolint:gochecknoglobals
+	// escapedVar matches the variables that mustache would HTML-escape, which YAML has no use for.
+	escapedVar = regexp.MustCompile(`(^|[^{])\{\{(\s*[\w.]+\s*)\}\}`)
+	// anyVar matches variables, escaped or not.
+	anyVar = regexp.MustCompile(`\{\{\{?\s*([\w.]+)\s*\}?\}\}`)
+)
+
+// rawVars is the key of the values as they are, without quotes, which comments use.
+const rawVars = "_raw"
+
+// actions are what templates can require of providers.
+var actions = []string{"read", "write", "proxy", "subscribe"} //nolint:gochecknoglobals
+
+var (
+	ErrUnknownTemplate = errors.New("unknown template")
+	ErrTemplateHeader  = errors.New("invalid template header")
+	ErrUnknownVar      = errors.New("unknown template variable")
+)
+
+// Template is a manifest written in mustache, which is filled in for a provider. It may start
+// with a mustache comment holding a YAML header, which describes the template:
+//
+//	{{!
+//	description: Two-way sync of CRM contacts
+//	requires: [read, write]
+//	vars:
+//	  object: contacts
+//	}}
+//
+// Besides its own variables, a template can use name, displayName, provider,
+// providerDisplayName, module and specVersion, and the booleans read, write, proxy,
+// subscribe, createEvent, updateEvent and deleteEvent that tell what the provider supports.
+// Variables that aren't empty are filled in as quoted YAML strings, so they are used without
+// quotes (`displayName: {{displayName}}`), except in comment lines, where they are filled in
+// as they are. Variables aren't HTML-escaped.
+type Template struct {
+	Name        string            `json:"-"`
+	Description string            `json:"description"`
+	Requires    []string          `json:"requires,omitempty"`
+	Vars        map[string]string `json:"vars,omitempty"`
+
+	// Dir is the directory the template comes from, empty for built-in templates.
+	Dir string `json:"-"`
+
+	body string
+}
+
+// Templates returns the built-in templates and those found in the directories, sorted by
+// name. A template replaces the built-in one or the one of an earlier directory that has the
+// same name. Missing directories are skipped.
+func Templates(dirs ...string) ([]*Template, error) {
+	byName := map[string]*Template{}
+
+	err := addTemplates(byName, builtinTemplates, "templates", "")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dir := range dirs {
+		if _, err := os.Stat(dir); errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+
+		err := addTemplates(byName, os.DirFS(dir), ".", dir)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	templates := make([]*Template, 0, len(byName))
+	for _, tmpl := range byName {
+		templates = append(templates, tmpl)
+	}
+
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+
+	return templates, nil
+}
+
+// UserTemplateDir is where users keep their own templates, which is looked in before the
+// directories given by --template-dir.
+func UserTemplateDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find the config directory: %w", err)
+	}
+
+	return filepath.Join(dir, "ampersand", "templates"), nil
+}
+
+// FindTemplate returns the template with the given name, looking in the same places as Templates.
+func FindTemplate(name string, dirs ...string) (*Template, error) {
+	templates, err := Templates(dirs...)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(templates))
+
+	for _, tmpl := range templates {
+		if tmpl.Name == name {
+			return tmpl, nil
+		}
+
+		names = append(names, tmpl.Name)
+	}
+
+	return nil, fmt.Errorf("%w %q (available: %s)", ErrUnknownTemplate, name, strings.Join(names, ", "))
+}
+
+func addTemplates(byName map[string]*Template, fsys fs.FS, dir, source string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return fmt.Errorf("failed to read templates: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != templateExt {
+			continue
+		}
+
+		data, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return fmt.Errorf("failed to read template: %w", err)
+		}
+
+		tmpl, err := parseTemplate(strings.TrimSuffix(entry.Name(), templateExt), string(data))
+		if err != nil {
+			if source != "" {
+				return fmt.Errorf("%s: %w", filepath.Join(source, entry.Name()), err)
+			}
+
+			return err
+		}
+
+		tmpl.Dir = source
+		byName[tmpl.Name] = tmpl
+	}
+
+	return nil
+}
+
+func parseTemplate(name, body string) (*Template, error) {
+	tmpl := &Template{Name: name, body: body}
+
+	if !strings.HasPrefix(body, "{{!") {
+		return tmpl, nil
+	}
+
+	end := strings.Index(body, "}}")
+	if end < 0 {
+		return nil, fmt.Errorf("%w: the comment of %s is not closed", ErrTemplateHeader, name)
+	}
+
+	err := yaml.UnmarshalStrict([]byte(body[len("{{!"):end]), tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("%w of %s: %w", ErrTemplateHeader, name, err)
+	}
+
+	tmpl.Name = name
+
+	for _, action := range tmpl.Requires {
+		if !slices.Contains(actions, action) {
+			return nil, fmt.Errorf("%w of %s: requires %q, but actions are %s", ErrTemplateHeader, name, action,
+				strings.Join(actions, ", "))
+		}
+	}
+
+	return tmpl, nil
+}
+
+// Render fills the template in for the integration, which uses the given module of the provider
+// (empty for the default one). The variables replace the template's defaults.
+func (t *Template) Render(integName, displayName string, provider *openapi.ProviderInfo, moduleName string,
+	vars map[string]string,
+) ([]byte, error) {
+	module, err := ModuleProvider(provider, moduleName)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, action := range t.Requires {
+		if !supports(module.Support, action) {
+			return nil, fmt.Errorf("the %s template needs %s, which is %w %s", t.Name, action, ErrUnsupported,
+				module.DisplayName)
+		}
+	}
+
+	// Only spell out the module when there is a choice, as Integration does
+	if moduleName == "" && provider.Modules != nil && len(*provider.Modules) > 1 {
+		moduleName = provider.DefaultModule
+	}
+
+	if displayName == "" {
+		displayName = DefaultDisplayName
+	}
+
+	events := SupportedEvents(module.Support)
+	data := map[string]any{
+		"name":                integName,
+		"displayName":         displayName,
+		"provider":            provider.Name,
+		"providerDisplayName": module.DisplayName,
+		"module":              moduleName,
+		"specVersion":         SpecVersion,
+		"read":                module.Support.Read,
+		"write":               module.Support.Write,
+		"proxy":               module.Support.Proxy,
+		"subscribe":           module.Support.Subscribe,
+		"createEvent":         events.Create,
+		"updateEvent":         events.Update,
+		"deleteEvent":         events.Delete,
+	}
+
+	for key, value := range t.Vars {
+		data[key] = value
+	}
+
+	for key, value := range vars {
+		if _, ok := t.Vars[key]; !ok {
+			if len(t.Vars) == 0 {
+				return nil, fmt.Errorf("%w %q, the %s template has none", ErrUnknownVar, key, t.Name)
+			}
+
+			return nil, fmt.Errorf("%w %q for the %s template (it has: %s)", ErrUnknownVar, key, t.Name,
+				strings.Join(slices.Sorted(maps.Keys(t.Vars)), ", "))
+		}
+
+		data[key] = value
+	}
+
+	// Values can hold anything, e.g. quotes in a display name, so they go in as quoted YAML strings,
+	// except in comments, where they are kept on one line instead
+	raw := map[string]any{}
+
+	for key, value := range data {
+		raw[key] = value
+
+		if text, ok := value.(string); ok && text != "" {
+			data[key] = quoteYAML(text)
+			raw[key] = strings.ReplaceAll(text, "\n", " ")
+		}
+	}
+
+	data[rawVars] = raw
+
+	renderer := mustache.New()
+
+	err = renderer.ParseString(prepareBody(t.body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the %s template: %w", t.Name, err)
+	}
+
+	out, err := renderer.RenderString(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render the %s template: %w", t.Name, err)
+	}
+
+	return unquote([]byte(strings.TrimLeft(out, "\n")))
+}
+
+// prepareBody points the variables in comment lines to the raw values, and keeps mustache from
+// HTML-escaping the others.
+func prepareBody(body string) string {
+	lines := strings.Split(body, "\n")
+
+	for idx, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			lines[idx] = anyVar.ReplaceAllString(line, "{{{"+rawVars+".$1}}}")
+		} else {
+			lines[idx] = escapedVar.ReplaceAllString(line, "$1{{{$2}}}")
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// unquote drops the quotes that the values were filled in with, for the strings that don't
+// need them, so that the manifest reads as if it were written by hand.
+func unquote(data []byte) ([]byte, error) {
+	doc := &Document{data: data, indent: 2, compactSeq: true} //nolint:mnd
+
+	err := doc.parse()
+	if err != nil {
+		return nil, err
+	}
+
+	var walk func(node *yamlv3.Node)
+
+	walk = func(node *yamlv3.Node) {
+		if node.Kind == yamlv3.ScalarNode && node.Tag == "!!str" {
+			node.Style &^= yamlv3.DoubleQuotedStyle
+		}
+
+		for _, child := range node.Content {
+			walk(child)
+		}
+	}
+
+	walk(&doc.node)
+
+	return doc.encode(&doc.node)
+}
+
+// quoteYAML returns the text as a YAML double-quoted string, which is a JSON string.
+func quoteYAML(text string) string {
+	var buf bytes.Buffer
+
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+
+	// Encoding a string can't fail
+	_ = enc.Encode(text)
+
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+func supports(support openapi.Support, action string) bool {
+	switch action {
+	case "read":
+		return support.Read
+	case "write":
+		return support.Write
+	case "proxy":
+		return support.Proxy
+	case "subscribe":
+		return support.Subscribe
+	default:
+		return false
+	}
+}
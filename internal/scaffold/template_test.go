@@ -0,0 +1,170 @@
+package scaffold
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/amp-labs/cli/files"
+	"github.com/amp-labs/cli/openapi"
+)
+
+func TestBuiltinTemplates(t *testing.T) {
+	t.Parallel()
+
+	provider := testProvider()
+	provider.Support.Subscribe = true
+
+	templates, err := Templates()
+	if err != nil {
+		t.Fatalf("Templates() returned %v", err)
+	}
+
+	if len(templates) == 0 {
+		t.Fatal("Templates() returned no templates")
+	}
+
+	for _, tmpl := range templates {
+		if tmpl.Description == "" || tmpl.Dir != "" {
+			t.Errorf("%s has description %q and directory %q", tmpl.Name, tmpl.Description, tmpl.Dir)
+		}
+
+		data, err := tmpl.Render("x", "", provider, "", map[string]string{})
+		if err != nil {
+			t.Errorf("Render() of %s returned %v", tmpl.Name, err)
+
+			continue
+		}
+
+		manifest, err := files.ParseManifest(data)
+		if err != nil {
+			t.Errorf("%s doesn't make a manifest: %v\n%s", tmpl.Name, err, data)
+
+			continue
+		}
+
+		err = files.ValidateManifest(manifest)
+		if err != nil {
+			t.Errorf("%s doesn't make a valid manifest: %v\n%s", tmpl.Name, err, data)
+		}
+	}
+}
+
+func TestRenderTemplate(t *testing.T) {
+	t.Parallel()
+
+	tmpl, err := FindTemplate("crm-contacts-sync")
+	if err != nil {
+		t.Fatalf("FindTemplate() returned %v", err)
+	}
+
+	provider := testProvider()
+	provider.DefaultModule = "crm"
+	provider.Modules = &openapi.Modules{
+		"crm":    {DisplayName: "Salesforce CRM", Support: provider.Support},
+		"pardot": {DisplayName: "Pardot", Support: openapi.Support{Read: true}},
+	}
+
+	data, err := tmpl.Render("sync", "Contact sync", provider, "", map[string]string{"object": "Contact"})
+	if err != nil {
+		t.Fatalf("Render() returned %v", err)
+	}
+
+	manifest, err := files.ParseManifest(data)
+	if err != nil {
+		t.Fatalf("ParseManifest() returned %v", err)
+	}
+
+	// Comments get the values as they are
+	if !strings.HasPrefix(string(data), "# Two-way sync of Salesforce CRM Contact: every contact") {
+		t.Errorf("unexpected header comment:\n%s", data)
+	}
+
+	integ := manifest.Integrations[0]
+	if integ.Name != "sync" || integ.DisplayName != "Contact sync" || integ.Module != "crm" {
+		t.Errorf("integration is %s (%s) of module %s", integ.Name, integ.DisplayName, integ.Module)
+	}
+
+	// The provider doesn't support subscribe, so the section is left out
+	if integ.Subscribe != nil || (*integ.Write.Objects)[0].ObjectName != "Contact" {
+		t.Errorf("unexpected manifest:\n%s", data)
+	}
+
+	// Values are quoted as needed, whatever they hold
+	data, err = tmpl.Render("sync", `A "B": C`, provider, "", map[string]string{"schedule": "*/5 * * * *"})
+	if err != nil {
+		t.Fatalf("Render() returned %v", err)
+	}
+
+	manifest, err = files.ParseManifest(data)
+	if err != nil {
+		t.Fatalf("ParseManifest() returned %v for:\n%s", err, data)
+	}
+
+	integ = manifest.Integrations[0]
+	if integ.DisplayName != `A "B": C` || (*integ.Read.Objects)[0].Schedule != "*/5 * * * *" ||
+		!strings.Contains(string(data), "\n- name: sync\n") {
+		t.Errorf("unexpected manifest:\n%s", data)
+	}
+
+	_, err = tmpl.Render("sync", "", provider, "pardot", nil)
+	if !errors.Is(err, ErrUnsupported) {
+		t.Errorf("Render() for a module without write returned %v, want ErrUnsupported", err)
+	}
+
+	_, err = tmpl.Render("sync", "", provider, "", map[string]string{"objet": "Contact"})
+	if !errors.Is(err, ErrUnknownVar) {
+		t.Errorf("Render() with an unknown variable returned %v, want ErrUnknownVar", err)
+	}
+}
+
+func TestUserTemplates(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(dir, "proxy-only.yaml"), []byte(`{{!
+description: Mine
+}}
+specVersion: {{specVersion}}
+integrations:
+- name: {{name}}
+  provider: {{provider}}
+  proxy:
+    enabled: true
+`), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl, err := FindTemplate("proxy-only", dir)
+	if err != nil {
+		t.Fatalf("FindTemplate() returned %v", err)
+	}
+
+	if tmpl.Description != "Mine" || tmpl.Dir != dir {
+		t.Errorf("FindTemplate() returned %q from %q, want the user's template", tmpl.Description, tmpl.Dir)
+	}
+
+	_, err = FindTemplate("proxy-only", filepath.Join(dir, "missing"))
+	if err != nil {
+		t.Errorf("FindTemplate() with a missing directory returned %v", err)
+	}
+
+	_, err = FindTemplate("nope", dir)
+	if !errors.Is(err, ErrUnknownTemplate) || !strings.Contains(err.Error(), "crm-contacts-sync") {
+		t.Errorf("FindTemplate() of a missing template returned %v, want ErrUnknownTemplate", err)
+	}
+
+	err = os.WriteFile(filepath.Join(dir, "bad.yaml"), []byte("{{!\nrequires: [sync]\n}}\n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = Templates(dir)
+	if !errors.Is(err, ErrTemplateHeader) {
+		t.Errorf("Templates() with a bad header returned %v, want ErrTemplateHeader", err)
+	}
+}
@@ -0,0 +1,149 @@
+// Package schedule parses the cron schedules of manifests, so that mistakes show up before
+// the manifest is deployed rather than after.
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+var ErrInvalid = errors.New("invalid cron schedule")
+
+var (
+	errTimeZone     = errors.New("a time zone can't be set, schedules run in UTC")
+	errEvery        = errors.New("@every isn't supported, use five fields, e.g. */10 * * * *")
+	errDescriptor   = errors.New("unknown descriptor")
+	errFieldCount   = errors.New("expected five fields (minute, hour, day of month, month, day of week)")
+	errQuestionMark = errors.New("? isn't supported, use * instead")
+)
+
+const (
+	// MinReadInterval is the shortest time between two scheduled reads that isn't
+	// considered too aggressive.
+	MinReadInterval = 10 * time.Minute
+
+	// MinWatchSchemaInterval is the shortest time between two schema checks.
+	MinWatchSchemaInterval = time.Hour
+
+	// intervalSamples is the number of runs looked at to find the shortest interval. A
+	// week of runs every 10 minutes is about a thousand.
+	intervalSamples = 2000
+
+	// fieldCount is the number of fields of a schedule that isn't a descriptor.
+	fieldCount = 5
+)
+
+// Schedule is a parsed cron schedule. Schedules run in UTC.
+type Schedule struct {
+	Expr string
+
+	spec cron.Schedule
+}
+
+// descriptors are the shorthands accepted in place of the five fields.
+var descriptors = []string{ //nolint:gochecknoglobals
+	"@yearly", "@annually", "@monthly", "@weekly", "@daily", "@midnight", "@hourly",
+}
+
+// Parse parses a cron schedule with five fields (minute, hour, day of month, month and day of
+// week), or a descriptor like @hourly. The extensions that the cron library also accepts,
+// such as @every 5m or a CRON_TZ= prefix, are rejected since the server doesn't take them.
+func Parse(expr string) (*Schedule, error) {
+	err := checkForm(expr)
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %w", ErrInvalid, expr, err)
+	}
+
+	spec, err := cron.ParseStandard(expr)
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %w", ErrInvalid, expr, err)
+	}
+
+	return &Schedule{Expr: expr, spec: spec}, nil
+}
+
+// checkForm rejects the forms of schedule that the cron library accepts but aren't standard.
+func checkForm(expr string) error {
+	fields := strings.Fields(expr)
+
+	switch {
+	case len(fields) > 0 && (strings.HasPrefix(fields[0], "TZ=") || strings.HasPrefix(fields[0], "CRON_TZ=")):
+		return errTimeZone
+	case len(fields) > 0 && fields[0] == "@every":
+		return errEvery
+	case len(fields) == 1 && strings.HasPrefix(fields[0], "@"):
+		if !slices.Contains(descriptors, fields[0]) {
+			return fmt.Errorf("%w, expected one of %s", errDescriptor, strings.Join(descriptors, ", "))
+		}
+
+		return nil
+	case len(fields) != fieldCount:
+		return errFieldCount
+	case strings.Contains(expr, "?"):
+		return errQuestionMark
+	default:
+		return nil
+	}
+}
+
+// Next returns the next count runs after the given time.
+func (s *Schedule) Next(after time.Time, count int) []time.Time {
+	runs := make([]time.Time, 0, max(count, 0))
+	next := after.UTC()
+
+	for range count {
+		next = s.spec.Next(next)
+		if next.IsZero() {
+			break
+		}
+
+		runs = append(runs, next)
+	}
+
+	return runs
+}
+
+// ShortestInterval returns the shortest time between two runs, looking at the runs that come
+// after the given time. It is zero if the schedule doesn't run at least twice.
+func (s *Schedule) ShortestInterval(after time.Time) time.Duration {
+	runs := s.Next(after, intervalSamples)
+
+	var shortest time.Duration
+
+	for idx := 1; idx < len(runs); idx++ {
+		interval := runs[idx].Sub(runs[idx-1])
+		if shortest == 0 || interval < shortest {
+			shortest = interval
+		}
+	}
+
+	return shortest
+}
+
+// FormatInterval writes an interval the way people do, e.g. 10m rather than 10m0s.
+func FormatInterval(interval time.Duration) string {
+	text := interval.String()
+
+	if strings.HasSuffix(text, "m0s") {
+		text = strings.TrimSuffix(text, "0s")
+	}
+
+	if strings.HasSuffix(text, "h0m") {
+		text = strings.TrimSuffix(text, "0m")
+	}
+
+	return text
+}
+
+// TooAggressive tells whether the schedule runs more often than the given interval allows,
+// along with its shortest interval.
+func (s *Schedule) TooAggressive(minInterval time.Duration) (bool, time.Duration) {
+	shortest := s.ShortestInterval(time.Now())
+
+	return shortest != 0 && shortest < minInterval, shortest
+}
@@ -0,0 +1,93 @@
+package schedule
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	for _, expr := range []string{"*/10 * * * *", "0 9 * * 1-5", "@hourly", "@midnight", "0 9 1 JAN-MAR MON"} {
+		_, err := Parse(expr)
+		if err != nil {
+			t.Errorf("Parse(%q) returned %v", expr, err)
+		}
+	}
+
+	for _, expr := range []string{
+		"", "*/10 * * *", "61 * * * *", "0 0 * * * *", "every hour",
+		"@every 5s", "@every 1h", "CRON_TZ=America/New_York 0 9 * * *", "TZ=UTC 0 9 * * *",
+		"@reboot", "0 0 ? * MON", "TZ=UTC @daily",
+	} {
+		_, err := Parse(expr)
+		if !errors.Is(err, ErrInvalid) {
+			t.Errorf("Parse(%q) returned %v, want ErrInvalid", expr, err)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	t.Parallel()
+
+	sched, err := Parse("30 9 * * 1-5")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A Friday evening in New York, which is already Saturday in UTC
+	after := time.Date(2026, 10, 16, 21, 0, 0, 0, time.FixedZone("EDT", -4*60*60))
+
+	runs := sched.Next(after, 2)
+	want := []time.Time{
+		time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC),
+		time.Date(2026, 10, 20, 9, 30, 0, 0, time.UTC),
+	}
+
+	if len(runs) != len(want) {
+		t.Fatalf("Next() = %v, want %v", runs, want)
+	}
+
+	for idx, run := range runs {
+		if !run.Equal(want[idx]) {
+			t.Errorf("Next()[%d] = %v, want %v", idx, run, want[idx])
+		}
+	}
+
+	if runs := sched.Next(after, -1); len(runs) != 0 {
+		t.Errorf("Next() with a negative count = %v, want no runs", runs)
+	}
+}
+
+func TestShortestInterval(t *testing.T) {
+	t.Parallel()
+
+	after := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+
+	for expr, want := range map[string]time.Duration{
+		"*/10 * * * *": 10 * time.Minute,
+		"0 */6 * * *":  6 * time.Hour,
+		"0 9,17 * * 1": 8 * time.Hour,
+		"15 3 * * 1-5": 24 * time.Hour,
+		// Once a day, but every minute of that hour
+		"* 9 * * *": time.Minute,
+	} {
+		sched, err := Parse(expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := sched.ShortestInterval(after); got != want {
+			t.Errorf("ShortestInterval() of %q = %s, want %s", expr, got, want)
+		}
+	}
+
+	if got := FormatInterval(90 * time.Minute); got != "1h30m" {
+		t.Errorf("FormatInterval(90m) = %q, want 1h30m", got)
+	}
+
+	if got := FormatInterval(time.Hour); got != "1h" {
+		t.Errorf("FormatInterval(1h) = %q, want 1h", got)
+	}
+}
@@ -0,0 +1,31 @@
+// Command gen writes the JSON Schema of amp.yaml from the Go types of the manifest.
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/amp-labs/cli/internal/schema"
+)
+
+func main() {
+	src := flag.String("src", "../../openapi/manifest.gen.go", "Go source of the manifest types")
+	out := flag.String("o", "manifest.schema.json", "File to write the schema to")
+	flag.Parse()
+
+	data, err := os.ReadFile(*src)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	schemaJSON, err := schema.Generate(data)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	err = os.WriteFile(*out, schemaJSON, 0o644) //nolint:gosec,mnd
+	if err != nil {
+		log.Fatal(err)
+	}
+}
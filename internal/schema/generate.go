@@ -0,0 +1,331 @@
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var ErrUnsupportedType = errors.New("type not supported by the schema generator")
+
+const (
+	rootType = "Manifest"
+
+	// draft is the version of JSON Schema written, which is the one editors support best.
+	draft = "http://json-schema.org/draft-07/schema#"
+)
+
+// boilerplate is the comment that oapi-codegen writes on types without a description.
+var boilerplate = regexp.MustCompile(`^\w+ defines model for \w+\.$`) //nolint:gochecknoglobals
+
+// Generate writes the JSON Schema of the manifest from the Go source of the manifest types,
+// which oapi-codegen generates from the OpenAPI spec. The descriptions come from the doc
+// comments, enums from the constants of string types, and unions from their As<Variant>
+// methods. Only the types that a manifest uses are in the schema.
+func Generate(src []byte) ([]byte, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), "manifest.gen.go", src, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the manifest types: %w", err)
+	}
+
+	gen := &generator{
+		types:    map[string]*ast.TypeSpec{},
+		docs:     map[string]string{},
+		enums:    map[string][]string{},
+		variants: map[string][]string{},
+		defs:     map[string]any{},
+	}
+
+	gen.collect(file)
+
+	err = gen.define(rootType)
+	if err != nil {
+		return nil, err
+	}
+
+	root, ok := gen.defs[rootType].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is not a struct", ErrUnsupportedType, rootType)
+	}
+
+	delete(gen.defs, rootType)
+
+	root["$schema"] = draft
+	root["title"] = "Ampersand manifest (amp.yaml)"
+	root["definitions"] = gen.defs
+
+	data, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal the schema: %w", err)
+	}
+
+	return append(data, '\n'), nil
+}
+
+type generator struct {
+	types    map[string]*ast.TypeSpec
+	docs     map[string]string
+	enums    map[string][]string
+	variants map[string][]string
+
+	// defs are the schemas of the types, by name
+	defs map[string]any
+}
+
+// collect finds the types of the file, their docs, the values of enums and the variants of
+// unions.
+func (g *generator) collect(file *ast.File) {
+	for _, decl := range file.Decls {
+		switch decl := decl.(type) {
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					g.types[spec.Name.Name] = spec
+					g.docs[spec.Name.Name] = description(spec.Name.Name, decl.Doc)
+				case *ast.ValueSpec:
+					g.collectEnum(decl.Tok, spec)
+				}
+			}
+		case *ast.FuncDecl:
+			g.collectVariant(decl)
+		}
+	}
+}
+
+func (g *generator) collectEnum(tok token.Token, spec *ast.ValueSpec) {
+	ident, ok := spec.Type.(*ast.Ident)
+	if tok != token.CONST || !ok || len(spec.Values) != 1 {
+		return
+	}
+
+	lit, ok := spec.Values[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return
+	}
+
+	value, err := strconv.Unquote(lit.Value)
+	if err == nil {
+		g.enums[ident.Name] = append(g.enums[ident.Name], value)
+	}
+}
+
+// collectVariant records the variant of a union that an As<Variant> method returns.
+func (g *generator) collectVariant(decl *ast.FuncDecl) {
+	if decl.Recv == nil || len(decl.Recv.List) != 1 || !strings.HasPrefix(decl.Name.Name, "As") {
+		return
+	}
+
+	recvType := decl.Recv.List[0].Type
+	if star, ok := recvType.(*ast.StarExpr); ok {
+		recvType = star.X
+	}
+
+	recv, ok := recvType.(*ast.Ident)
+	if !ok {
+		return
+	}
+
+	g.variants[recv.Name] = append(g.variants[recv.Name], strings.TrimPrefix(decl.Name.Name, "As"))
+}
+
+// define adds the schema of the named type, and of the types it uses, to the definitions.
+func (g *generator) define(name string) error {
+	if _, ok := g.defs[name]; ok {
+		return nil
+	}
+
+	spec, ok := g.types[name]
+	if !ok {
+		return fmt.Errorf("%w: %s is not declared", ErrUnsupportedType, name)
+	}
+
+	// Reserve the name, for types that refer to themselves
+	g.defs[name] = nil
+
+	schema, err := g.typeSchema(name, spec.Type)
+	if err != nil {
+		return err
+	}
+
+	if doc := g.docs[name]; doc != "" {
+		schema["description"] = doc
+	}
+
+	g.defs[name] = schema
+
+	return nil
+}
+
+func (g *generator) typeSchema(name string, expr ast.Expr) (map[string]any, error) {
+	if variants, ok := g.variants[name]; ok {
+		anyOf := make([]any, 0, len(variants))
+
+		for _, variant := range variants {
+			ref, err := g.exprSchema(ast.NewIdent(variant))
+			if err != nil {
+				return nil, err
+			}
+
+			anyOf = append(anyOf, ref)
+		}
+
+		return map[string]any{"anyOf": anyOf}, nil
+	}
+
+	if st, ok := expr.(*ast.StructType); ok {
+		return g.structSchema(st)
+	}
+
+	schema, err := g.exprSchema(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	if values, ok := g.enums[name]; ok {
+		schema["enum"] = values
+	}
+
+	return schema, nil
+}
+
+func (g *generator) structSchema(st *ast.StructType) (map[string]any, error) {
+	properties := map[string]any{}
+	required := []string{}
+
+	for _, field := range st.Fields.List {
+		if field.Tag == nil || len(field.Names) != 1 {
+			continue
+		}
+
+		tag, err := strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the tag of %s: %w", field.Names[0].Name, err)
+		}
+
+		jsonName, opts, _ := strings.Cut(reflect.StructTag(tag).Get("json"), ",")
+		if jsonName == "" || jsonName == "-" {
+			continue
+		}
+
+		prop, err := g.exprSchema(field.Type)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Names[0].Name, err)
+		}
+
+		if doc := description(field.Names[0].Name, field.Doc); doc != "" {
+			// Keywords next to a reference are ignored in draft 7
+			if _, isRef := prop["$ref"]; isRef {
+				prop = map[string]any{"allOf": []any{prop}}
+			}
+
+			prop["description"] = doc
+		}
+
+		addMinimum(prop, reflect.StructTag(tag).Get("validate"))
+
+		properties[jsonName] = prop
+
+		_, isPointer := field.Type.(*ast.StarExpr)
+		if !strings.Contains(opts, "omitempty") && !isPointer {
+			required = append(required, jsonName)
+		}
+	}
+
+	sort.Strings(required)
+
+	schema := map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	return schema, nil
+}
+
+func (g *generator) exprSchema(expr ast.Expr) (map[string]any, error) {
+	switch expr := expr.(type) {
+	case *ast.StarExpr:
+		return g.exprSchema(expr.X)
+	case *ast.ArrayType:
+		items, err := g.exprSchema(expr.Elt)
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]any{"type": "array", "items": items}, nil
+	case *ast.MapType:
+		values, err := g.exprSchema(expr.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]any{"type": "object", "additionalProperties": values}, nil
+	case *ast.InterfaceType:
+		return map[string]any{}, nil
+	case *ast.Ident:
+		switch expr.Name {
+		case "string":
+			return map[string]any{"type": "string"}, nil
+		case "bool":
+			return map[string]any{"type": "boolean"}, nil
+		case "int", "int32", "int64":
+			return map[string]any{"type": "integer"}, nil
+		case "float32", "float64":
+			return map[string]any{"type": "number"}, nil
+		case "any":
+			return map[string]any{}, nil
+		}
+
+		err := g.define(expr.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]any{"$ref": "#/definitions/" + expr.Name}, nil
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedType, expr)
+	}
+}
+
+// addMinimum copies the min= rule of a validate tag to a number schema.
+func addMinimum(schema map[string]any, rules string) {
+	if schema["type"] != "integer" && schema["type"] != "number" {
+		return
+	}
+
+	for rule := range strings.SplitSeq(rules, ",") {
+		if value, ok := strings.CutPrefix(rule, "min="); ok {
+			if minimum, err := strconv.Atoi(value); err == nil {
+				schema["minimum"] = minimum
+			}
+		}
+	}
+}
+
+// description turns a doc comment into a description. oapi-codegen starts the comments with
+// the name of what they describe, and writes a placeholder when there is no description.
+func description(name string, doc *ast.CommentGroup) string {
+	if doc == nil {
+		return ""
+	}
+
+	text := strings.Join(strings.Fields(doc.Text()), " ")
+	if boilerplate.MatchString(text) {
+		return ""
+	}
+
+	return strings.TrimSpace(strings.TrimPrefix(text, name))
+}
@@ -0,0 +1,155 @@
+// Package schema holds the JSON Schema of amp.yaml, which editors use to complete and check
+// manifests, and which 'amp validate' checks them against, so that the two always agree.
+package schema
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/santhosh-tekuri/jsonschema/v6/kind"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"sigs.k8s.io/yaml"
+)
+
+// schemaURL is where the schema is found, as far as the validator is concerned.
+const schemaURL = "amp.schema.json"
+
+var ErrInvalid = errors.New("the manifest doesn't match the schema")
+
+// printer writes the messages of violations.
+var printer = message.NewPrinter(language.English) //nolint:gochecknoglobals
+
+//go:generate go run ./gen -src ../../openapi/manifest.gen.go -o manifest.schema.json
+//go:embed manifest.schema.json
+var manifestSchema []byte
+
+// JSON returns the JSON Schema of amp.yaml.
+func JSON() []byte {
+	return manifestSchema
+}
+
+// compiled is the schema, compiled the first time a manifest is validated.
+var compiled = sync.OnceValues(func() (*jsonschema.Schema, error) { //nolint:gochecknoglobals
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(manifestSchema))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the schema: %w", err)
+	}
+
+	compiler := jsonschema.NewCompiler()
+
+	err = compiler.AddResource(schemaURL, doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the schema: %w", err)
+	}
+
+	sch, err := compiler.Compile(schemaURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile the schema: %w", err)
+	}
+
+	return sch, nil
+})
+
+// Violation is a place where a manifest doesn't match the schema.
+type Violation struct {
+	// Path is the YAML path of the value at fault, e.g. $.integrations[0].read
+	Path    string
+	Message string
+}
+
+func (v Violation) String() string {
+	return v.Path + ": " + v.Message
+}
+
+// Validate checks a manifest, in YAML, against the schema. It returns the places where the
+// manifest doesn't match, and an error wrapping ErrInvalid if there are any.
+func Validate(data []byte) ([]Violation, error) {
+	sch, err := compiled()
+	if err != nil {
+		return nil, err
+	}
+
+	jsonData, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse yaml: %w", err)
+	}
+
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse yaml: %w", err)
+	}
+
+	err = sch.Validate(doc)
+	if err == nil {
+		return nil, nil
+	}
+
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &validationErr) {
+		return nil, fmt.Errorf("failed to validate the manifest: %w", err)
+	}
+
+	violations := violations(validationErr, nil)
+
+	return violations, fmt.Errorf("%w (%d problems)", ErrInvalid, len(violations))
+}
+
+// violations lists the errors of the tree that are about a value, rather than the ones
+// that only say that one of their causes failed, and adds them to found.
+func violations(err *jsonschema.ValidationError, found []Violation) []Violation {
+	if len(err.Causes) == 0 {
+		violation := Violation{
+			Path:    yamlPath(err.InstanceLocation),
+			Message: err.ErrorKind.LocalizedString(printer),
+		}
+
+		if !slices.Contains(found, violation) {
+			found = append(found, violation)
+		}
+
+		return found
+	}
+
+	causes := err.Causes
+
+	// A union matches none of its variants: the closest one tells best what is wrong
+	if _, ok := err.ErrorKind.(*kind.AnyOf); ok {
+		closest := slices.MinFunc(causes, func(a, b *jsonschema.ValidationError) int {
+			return len(violations(a, nil)) - len(violations(b, nil))
+		})
+
+		causes = []*jsonschema.ValidationError{closest}
+	}
+
+	for _, cause := range causes {
+		found = violations(cause, found)
+	}
+
+	return found
+}
+
+// yamlPath turns a JSON pointer, split into its tokens, into a YAML path: [integrations 0 name]
+// becomes $.integrations[0].name.
+func yamlPath(tokens []string) string {
+	var sb strings.Builder
+
+	sb.WriteString("$")
+
+	for _, token := range tokens {
+		if _, err := strconv.Atoi(token); err == nil {
+			sb.WriteString("[" + token + "]")
+		} else {
+			sb.WriteString("." + token)
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,94 @@
+package schema
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestSchemaIsGenerated(t *testing.T) {
+	t.Parallel()
+
+	src, err := os.ReadFile("../../openapi/manifest.gen.go")
+	if err != nil {
+		t.Fatalf("failed to read the manifest types: %v", err)
+	}
+
+	generated, err := Generate(src)
+	if err != nil {
+		t.Fatalf("Generate() returned %v", err)
+	}
+
+	if !bytes.Equal(generated, JSON()) {
+		t.Error("manifest.schema.json is out of date, run go generate ./internal/schema")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	valid := `specVersion: 1.0.0
+integrations:
+- name: x
+  provider: salesforce
+  read:
+    objects:
+    - objectName: Account
+      destination: hook
+      schedule: "*/10 * * * *"
+      requiredFields:
+      - fieldName: Id
+      - mapToName: owner
+        mapToDisplayName: Owner
+        prompt: Which field holds the owner?
+  proxy:
+    enabled: true
+`
+
+	violations, err := Validate([]byte(valid))
+	if err != nil || len(violations) != 0 {
+		t.Fatalf("Validate() = %v, %v, want no violations", violations, err)
+	}
+
+	invalid := `specVersion: 1.0.0
+integrations:
+- name: x
+  provider: salesforce
+  read:
+    objects:
+    - objectName: Account
+      destination: hook
+      schedule: "*/10 * * * *"
+      delivery: {mode: push}
+      requiredFields:
+      - nme: Id
+  proxy:
+    enable: true
+`
+
+	violations, err = Validate([]byte(invalid))
+	if !errors.Is(err, ErrInvalid) {
+		t.Fatalf("Validate() returned %v, want ErrInvalid", err)
+	}
+
+	want := map[string]bool{
+		"$.integrations[0].proxy":                             false,
+		"$.integrations[0].read.objects[0].delivery.mode":     false,
+		"$.integrations[0].read.objects[0].requiredFields[0]": false,
+	}
+
+	for _, violation := range violations {
+		if _, ok := want[violation.Path]; !ok {
+			t.Errorf("unexpected violation %s", violation)
+		}
+
+		want[violation.Path] = true
+	}
+
+	for path, found := range want {
+		if !found {
+			t.Errorf("Validate() = %v, want a violation at %s", violations, path)
+		}
+	}
+}
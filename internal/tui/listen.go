@@ -0,0 +1,581 @@
+// Package tui implements the interactive terminal UI of `amp listen`.
+// It only depends on golang.org/x/term and plain ANSI escape sequences.
+package tui
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/amp-labs/cli/internal/webhook"
+	"golang.org/x/term"
+)
+
+const (
+	clearScreen     = "\x1b[2J\x1b[H"
+	enterAltScreen  = "\x1b[?1049h\x1b[?25l"
+	leaveAltScreen  = "\x1b[?25h\x1b[?1049l"
+	styleReset      = "\x1b[0m"
+	styleInverse    = "\x1b[7m"
+	styleBold       = "\x1b[1m"
+	styleDim        = "\x1b[2m"
+	styleRed        = "\x1b[31m"
+	styleGreen      = "\x1b[32m"
+	styleYellow     = "\x1b[33m"
+	helpText        = "↑/↓ move  enter details  pgup/pgdn scroll  / filter  r replay  c copy  q quit"
+	minListHeight   = 3
+	chromeLines     = 3 // title, column header and footer
+	readBufferBytes = 64
+)
+
+// ListenUI shows received events in a scrolling list with a detail pane.
+// All methods are safe to call from any goroutine.
+type ListenUI struct {
+	// OnReplay is called when the user asks to replay the selected event.
+	// It returns a message to show in the footer.
+	OnReplay func(evt *webhook.Event) string
+
+	in     *os.File
+	out    io.Writer
+	title  string
+	redraw chan struct{}
+
+	mu           sync.Mutex
+	events       []*webhook.Event
+	selected     int
+	follow       bool
+	filter       string
+	searching    bool
+	input        string
+	showDetail   bool
+	detailScroll int
+	status       string
+}
+
+// New returns a UI reading keys from in and drawing on out, with title on its top line.
+func New(in *os.File, out io.Writer, title string) *ListenUI {
+	return &ListenUI{
+		in:     in,
+		out:    out,
+		title:  title,
+		redraw: make(chan struct{}, 1),
+		follow: true,
+	}
+}
+
+// Upsert adds an event to the list, or refreshes it if it is already shown.
+func (u *ListenUI) Upsert(evt *webhook.Event) {
+	// Keep a private copy, since the listener keeps updating its own.
+	cp := evt.Clone()
+
+	u.mu.Lock()
+
+	idx := slices.IndexFunc(u.events, func(e *webhook.Event) bool { return e.Id == evt.Id })
+	if idx >= 0 {
+		u.events[idx] = cp
+	} else {
+		u.events = append(u.events, cp)
+	}
+
+	u.mu.Unlock()
+	u.requestRedraw()
+}
+
+// Status shows a message in the footer until the next key press.
+func (u *ListenUI) Status(msg string) {
+	u.mu.Lock()
+	u.status = msg
+	u.mu.Unlock()
+	u.requestRedraw()
+}
+
+func (u *ListenUI) requestRedraw() {
+	select {
+	case u.redraw <- struct{}{}:
+	default:
+	}
+}
+
+// Run takes over the terminal until ctx is done or the user quits, and restores it before returning.
+func (u *ListenUI) Run(ctx context.Context) error {
+	fd := int(u.in.Fd()) //nolint:gosec
+
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("failed to switch terminal to raw mode: %w", err)
+	}
+
+	defer func() {
+		_, _ = io.WriteString(u.out, leaveAltScreen)
+		_ = term.Restore(fd, state)
+	}()
+
+	_, _ = io.WriteString(u.out, enterAltScreen)
+
+	keys := make(chan []byte)
+	stopKeys := make(chan struct{})
+	readerDone := make(chan struct{})
+
+	go func() {
+		defer close(readerDone)
+
+		u.readKeys(keys, stopKeys)
+	}()
+
+	defer func() {
+		close(stopKeys)
+
+		// Interrupt the pending read where the input supports it. A terminal doesn't on
+		// every platform, its read then ends with the next key press, which is dropped.
+		if u.in.SetReadDeadline(time.Now()) == nil {
+			<-readerDone
+		}
+	}()
+
+	// Terminal resizes aren't signaled portably, so also redraw periodically.
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	u.draw()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case key, ok := <-keys:
+			if !ok || u.handleKey(key) {
+				return nil
+			}
+		case <-u.redraw:
+		case <-ticker.C:
+		}
+
+		u.draw()
+	}
+}
+
+// readKeys sends the keys read from the input until it fails, or until stop is closed.
+func (u *ListenUI) readKeys(keys chan<- []byte, stop <-chan struct{}) {
+	defer close(keys)
+
+	buf := make([]byte, readBufferBytes)
+
+	for {
+		n, err := u.in.Read(buf)
+		if err != nil {
+			return
+		}
+
+		for _, key := range splitKeys(buf[:n]) {
+			select {
+			case keys <- key:
+			case <-stop:
+				return
+			}
+		}
+	}
+}
+
+// splitKeys splits raw terminal input into individual key presses, since pasted
+// text or fast typing can arrive in a single read.
+func splitKeys(input []byte) [][]byte {
+	var keys [][]byte
+
+	for len(input) > 0 {
+		size := 1
+
+		switch {
+		case input[0] == 0x1b && len(input) > 2 && input[1] == '[':
+			// CSI sequence, e.g. "\x1b[A" or "\x1b[5~", ends with a byte in the range @ to ~
+			size = 2
+			for size < len(input) && (input[size] < '@' || input[size] > '~') {
+				size++
+			}
+
+			size = min(size+1, len(input))
+		case input[0] >= utf8.RuneSelf:
+			_, size = utf8.DecodeRune(input)
+		}
+
+		keys = append(keys, slices.Clone(input[:size]))
+		input = input[size:]
+	}
+
+	return keys
+}
+
+// handleKey applies a key press and reports whether the user asked to quit.
+func (u *ListenUI) handleKey(key []byte) bool { //nolint:cyclop,funlen
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.status = ""
+
+	if u.searching {
+		u.handleSearchKey(key)
+
+		return false
+	}
+
+	visible := u.visible()
+
+	switch string(key) {
+	case "q", "\x03":
+		return true
+	case "k", "\x1b[A":
+		u.move(-1, len(visible))
+	case "j", "\x1b[B":
+		u.move(1, len(visible))
+	case "g", "\x1b[H":
+		u.move(-len(visible), len(visible))
+	case "G", "\x1b[F":
+		u.move(len(visible), len(visible))
+	case "\x1b[5~":
+		u.detailScroll = max(0, u.detailScroll-u.pageSize())
+	case "\x1b[6~":
+		u.detailScroll += u.pageSize()
+	case "\r", "\t":
+		u.showDetail = !u.showDetail
+		u.detailScroll = 0
+	case "/":
+		u.searching = true
+		u.input = u.filter
+	case "\x1b":
+		u.filter = ""
+		u.showDetail = false
+	case "r":
+		if evt := u.current(visible); evt != nil && u.OnReplay != nil {
+			// Replaying blocks on the network, so run it outside the lock.
+			go func() {
+				u.Status("replaying " + evt.Id + "...")
+				u.Status(u.OnReplay(evt))
+			}()
+		}
+	case "c":
+		if evt := u.current(visible); evt != nil {
+			// OSC 52 asks the terminal itself to set the clipboard, which also works over SSH.
+			_, _ = io.WriteString(u.out, "\x1b]52;c;"+base64.StdEncoding.EncodeToString(evt.Body)+"\a")
+			u.status = "copied payload of " + evt.Id + " to the clipboard"
+		}
+	}
+
+	return false
+}
+
+func (u *ListenUI) handleSearchKey(key []byte) {
+	switch string(key) {
+	case "\r":
+		u.filter = u.input
+		u.searching = false
+		u.selected = 0
+		u.follow = true
+	case "\x1b", "\x03":
+		u.searching = false
+	case "\x7f", "\b":
+		if len(u.input) > 0 {
+			runes := []rune(u.input)
+			u.input = string(runes[:len(runes)-1])
+		}
+	default:
+		if key[0] >= ' ' && key[0] != 0x7f {
+			u.input += string(key)
+		}
+	}
+}
+
+func (u *ListenUI) move(delta, count int) {
+	u.selected = min(max(u.selected+delta, 0), max(count-1, 0))
+	u.follow = u.selected == count-1
+	u.detailScroll = 0
+}
+
+func (u *ListenUI) pageSize() int {
+	_, height := u.size()
+
+	return max(height/2-chromeLines, 1)
+}
+
+// visible returns the events matching the current filter, oldest first.
+func (u *ListenUI) visible() []*webhook.Event {
+	if u.filter == "" {
+		return u.events
+	}
+
+	needle := strings.ToLower(u.filter)
+
+	var out []*webhook.Event
+
+	for _, evt := range u.events {
+		eventType, object := webhook.Describe(evt.Body)
+
+		haystack := strings.ToLower(strings.Join([]string{
+			evt.Id, evt.Path, eventType, object, outcome(evt), string(evt.Body),
+		}, "\n"))
+
+		if strings.Contains(haystack, needle) {
+			out = append(out, evt)
+		}
+	}
+
+	return out
+}
+
+func (u *ListenUI) current(visible []*webhook.Event) *webhook.Event {
+	if len(visible) == 0 {
+		return nil
+	}
+
+	return visible[min(u.selected, len(visible)-1)]
+}
+
+func (u *ListenUI) size() (int, int) {
+	width, height, err := term.GetSize(int(u.in.Fd())) //nolint:gosec
+	if err != nil {
+		const fallbackWidth, fallbackHeight = 80, 24
+
+		return fallbackWidth, fallbackHeight
+	}
+
+	return width, height
+}
+
+func (u *ListenUI) draw() { //nolint:funlen
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	width, height := u.size()
+	visible := u.visible()
+
+	if u.follow {
+		u.selected = max(len(visible)-1, 0)
+	}
+
+	u.selected = min(u.selected, max(len(visible)-1, 0))
+
+	listHeight := height - chromeLines
+	detailHeight := 0
+
+	if u.showDetail {
+		listHeight = max((height-chromeLines)/2, minListHeight)
+		detailHeight = height - chromeLines - listHeight - 1
+	}
+
+	var screen strings.Builder
+
+	screen.WriteString(clearScreen)
+	writeLine(&screen, styleInverse, pad(u.title, width), width)
+	writeLine(&screen, styleBold, fmt.Sprintf("%-8s  %-24s  %-16s  %-18s  %s",
+		"TIME", "EVENT", "OBJECT", "STATUS", "LATENCY"), width)
+
+	// Scroll the list so that the selected row is always shown.
+	first := max(0, u.selected-listHeight+1)
+
+	for row := range listHeight {
+		idx := first + row
+		if idx >= len(visible) {
+			screen.WriteString("\r\n")
+
+			continue
+		}
+
+		style := statusStyle(visible[idx])
+		if idx == u.selected {
+			style = styleInverse
+		}
+
+		writeLine(&screen, style, pad(eventRow(visible[idx]), width), width)
+	}
+
+	if u.showDetail {
+		writeLine(&screen, styleDim, strings.Repeat("─", width), width)
+
+		lines := detailLines(u.current(visible))
+		u.detailScroll = min(u.detailScroll, max(len(lines)-detailHeight, 0))
+
+		for row := range detailHeight {
+			idx := u.detailScroll + row
+			if idx < len(lines) {
+				writeLine(&screen, "", lines[idx], width)
+			} else {
+				screen.WriteString("\r\n")
+			}
+		}
+	}
+
+	footer := helpText
+
+	switch {
+	case u.searching:
+		footer = "/" + u.input + "█"
+	case u.status != "":
+		footer = u.status
+	case u.filter != "":
+		footer = fmt.Sprintf("filter %q: %d of %d events (esc to clear)  %s", u.filter, len(visible), len(u.events), helpText)
+	}
+
+	screen.WriteString(styleDim + truncate(footer, width) + styleReset)
+
+	_, _ = io.WriteString(u.out, screen.String())
+}
+
+func writeLine(screen *strings.Builder, style, text string, width int) {
+	screen.WriteString(style + truncate(text, width) + styleReset + "\r\n")
+}
+
+func truncate(text string, width int) string {
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+
+	return string(runes[:max(width-1, 0)]) + "…"
+}
+
+func pad(text string, width int) string {
+	if n := len([]rune(text)); n < width {
+		return text + strings.Repeat(" ", width-n)
+	}
+
+	return text
+}
+
+func eventRow(evt *webhook.Event) string {
+	eventType, object := webhook.Describe(evt.Body)
+
+	return fmt.Sprintf("%-8s  %-24s  %-16s  %-18s  %s",
+		evt.ReceivedAt.Local().Format(time.TimeOnly),
+		truncate(orDash(eventType), 24), //nolint:mnd
+		truncate(orDash(object), 16),    //nolint:mnd
+		truncate(outcome(evt), 18),      //nolint:mnd
+		latency(evt)) + manifestMarker(evt)
+}
+
+// manifestMarker flags events that don't match the manifest given to amp listen.
+func manifestMarker(evt *webhook.Event) string {
+	if len(evt.ManifestFindings) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("  ⚠ %d manifest issue(s)", len(evt.ManifestFindings))
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+
+	return s
+}
+
+// outcome is the status column: the primary forward's status, the mock status, or the error.
+func outcome(evt *webhook.Event) string {
+	switch {
+	case evt.MockStatus != 0:
+		return strconv.Itoa(evt.MockStatus) + " (mock)"
+	case len(evt.Forwards) == 0:
+		return "not forwarded"
+	case evt.Forwards[0].Error != "":
+		return "failed"
+	default:
+		status := strconv.Itoa(evt.Forwards[0].StatusCode) + " " + http.StatusText(evt.Forwards[0].StatusCode)
+		if len(evt.Forwards) > 1 {
+			status += fmt.Sprintf(" +%d", len(evt.Forwards)-1)
+		}
+
+		return status
+	}
+}
+
+func latency(evt *webhook.Event) string {
+	if len(evt.Forwards) == 0 {
+		return "-"
+	}
+
+	return evt.Forwards[0].Latency.Round(time.Millisecond).String()
+}
+
+func statusStyle(evt *webhook.Event) string {
+	code := evt.MockStatus
+
+	if len(evt.Forwards) > 0 {
+		if !evt.Forwards[0].Delivered() {
+			return styleRed
+		}
+
+		code = evt.Forwards[0].StatusCode
+	}
+
+	switch {
+	case code >= http.StatusBadRequest:
+		return styleYellow
+	case code != 0:
+		return styleGreen
+	default:
+		return ""
+	}
+}
+
+func detailLines(evt *webhook.Event) []string {
+	if evt == nil {
+		return []string{"no event selected"}
+	}
+
+	lines := []string{
+		"ID:       " + evt.Id,
+		"Received: " + evt.ReceivedAt.Local().Format(time.RFC3339Nano),
+		"Request:  " + evt.Method + " " + evt.Path,
+	}
+
+	for _, result := range evt.Forwards {
+		status := strconv.Itoa(result.StatusCode) + " " + http.StatusText(result.StatusCode)
+		if result.Error != "" {
+			status = "failed: " + result.Error
+		}
+
+		lines = append(lines, fmt.Sprintf("Forward:  %s → %s (%s)",
+			result.URL, status, result.Latency.Round(time.Millisecond)))
+	}
+
+	for _, finding := range evt.ManifestFindings {
+		lines = append(lines, "Manifest: ⚠ "+finding)
+	}
+
+	names := make([]string, 0, len(evt.Headers))
+	for name := range evt.Headers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	lines = append(lines, "", "Headers:")
+
+	for _, name := range names {
+		for _, value := range evt.Headers[name] {
+			lines = append(lines, "  "+name+": "+value)
+		}
+	}
+
+	lines = append(lines, "", "Body:")
+
+	var body bytes.Buffer
+
+	err := json.Indent(&body, evt.Body, "", "  ")
+	if err != nil {
+		body.Reset()
+		body.Write(evt.Body)
+	}
+
+	return append(lines, strings.Split(body.String(), "\n")...)
+}
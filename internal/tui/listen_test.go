@@ -0,0 +1,181 @@
+package tui
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amp-labs/cli/internal/webhook"
+)
+
+// newTestUI returns a UI reading from a pipe, which it draws at the fallback size of 80x24.
+func newTestUI(t *testing.T) (*ListenUI, *bytes.Buffer, *os.File) {
+	t.Helper()
+
+	in, keys, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = in.Close()
+		_ = keys.Close()
+	})
+
+	var out bytes.Buffer
+
+	return New(in, &out, "amp listen · test"), &out, keys
+}
+
+func testEvent(id string, status int) *webhook.Event {
+	return &webhook.Event{
+		Id:         id,
+		ReceivedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		Method:     http.MethodPost,
+		Path:       "/webhook",
+		Headers:    http.Header{"X-Test": {"yes"}},
+		Body:       []byte(`{"type":"read","objectName":"contact"}`),
+		Forwards:   []*webhook.ForwardResult{{URL: "http://localhost:4000", StatusCode: status}},
+	}
+}
+
+func TestSplitKeys(t *testing.T) {
+	t.Parallel()
+
+	got := splitKeys([]byte("/hé\x1b[A\x1b[5~\r\x1b"))
+	want := []string{"/", "h", "é", "\x1b[A", "\x1b[5~", "\r", "\x1b"}
+
+	if len(got) != len(want) {
+		t.Fatalf("splitKeys() returned %q, want %q", got, want)
+	}
+
+	for i := range want {
+		if string(got[i]) != want[i] {
+			t.Errorf("splitKeys()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestUpsert(t *testing.T) {
+	t.Parallel()
+
+	ui, _, _ := newTestUI(t)
+
+	evt := testEvent("evt_1", http.StatusServiceUnavailable)
+	ui.Upsert(evt)
+	ui.Upsert(testEvent("evt_2", http.StatusOK))
+
+	// The UI keeps its own copy, changes only show once the event is upserted again
+	evt.Forwards[0].StatusCode = http.StatusOK
+
+	if got := outcome(ui.events[0]); got != "503 Service Unavailable" {
+		t.Errorf("outcome before the update = %q, want the status that was shown", got)
+	}
+
+	ui.Upsert(evt)
+
+	if len(ui.events) != 2 {
+		t.Fatalf("UI shows %d events, want 2", len(ui.events))
+	}
+
+	if ui.events[0].Id != "evt_1" || outcome(ui.events[0]) != "200 OK" {
+		t.Errorf("first event is %s with %q, want evt_1 refreshed in place", ui.events[0].Id, outcome(ui.events[0]))
+	}
+}
+
+func TestStatus(t *testing.T) {
+	t.Parallel()
+
+	ui, out, _ := newTestUI(t)
+
+	ui.Status("redelivered evt_1")
+	ui.draw()
+
+	if !strings.Contains(out.String(), "redelivered evt_1") {
+		t.Errorf("footer doesn't show the status:\n%q", out.String())
+	}
+
+	// A key press clears it
+	ui.handleKey([]byte("j"))
+	out.Reset()
+	ui.draw()
+
+	if strings.Contains(out.String(), "redelivered evt_1") || !strings.Contains(out.String(), helpText) {
+		t.Errorf("footer still shows the status after a key press:\n%q", out.String())
+	}
+}
+
+func TestDraw(t *testing.T) {
+	t.Parallel()
+
+	ui, out, _ := newTestUI(t)
+
+	ui.Upsert(testEvent("evt_1", http.StatusOK))
+
+	failed := testEvent("evt_2", 0)
+	failed.Body = []byte(`{"type":"write","objectName":"account"}`)
+	failed.Forwards[0].Error = "connection refused"
+	ui.Upsert(failed)
+
+	ui.draw()
+
+	screen := out.String()
+	for _, want := range []string{"amp listen · test", "EVENT", "contact", "200 OK", "account", "failed"} {
+		if !strings.Contains(screen, want) {
+			t.Errorf("screen doesn't show %q:\n%q", want, screen)
+		}
+	}
+
+	// Filter on the object, then open the details of the event that is left
+	for _, key := range splitKeys([]byte("/contact\r\r")) {
+		ui.handleKey(key)
+	}
+
+	out.Reset()
+	ui.draw()
+
+	screen = out.String()
+	for _, want := range []string{"ID:       evt_1", "X-Test: yes", "Body:", "1 of 2 events"} {
+		if !strings.Contains(screen, want) {
+			t.Errorf("screen doesn't show %q:\n%q", want, screen)
+		}
+	}
+
+	if strings.Contains(screen, "account") {
+		t.Errorf("screen shows the filtered out event:\n%q", screen)
+	}
+}
+
+func TestReadKeysStops(t *testing.T) {
+	t.Parallel()
+
+	ui, _, input := newTestUI(t)
+
+	keys := make(chan []byte)
+	stop := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		ui.readKeys(keys, stop)
+	}()
+
+	_, _ = input.WriteString("ab")
+
+	if key := <-keys; string(key) != "a" {
+		t.Fatalf("first key is %q, want a", key)
+	}
+
+	// Nobody reads the remaining key once the UI has stopped
+	close(stop)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("readKeys() kept running after stop was closed")
+	}
+}
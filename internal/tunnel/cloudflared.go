@@ -0,0 +1,57 @@
+package tunnel
+
+import (
+	"context"
+	"net/http"
+)
+
+// CloudflaredProvider reads the hostname of a quick tunnel ("cloudflared tunnel --url ...")
+// from cloudflared's metrics server. Start cloudflared with --metrics localhost:20241 to
+// make the address predictable.
+type CloudflaredProvider struct {
+	endpoint string
+	host     string
+	client   *http.Client
+}
+
+// cloudflaredResponse is the response of the metrics server's /quicktunnel endpoint.
+type cloudflaredResponse struct {
+	Hostname string `json:"hostname"`
+}
+
+// NewCloudflared returns a provider for the cloudflared metrics server at metricsURL,
+// e.g. "http://localhost:20241".
+func NewCloudflared(metricsURL string) (*CloudflaredProvider, error) {
+	parsed, err := parseAgentURL(metricsURL)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed.Path = "/quicktunnel"
+
+	return &CloudflaredProvider{endpoint: parsed.String(), host: parsed.Host, client: http.DefaultClient}, nil
+}
+
+func (p *CloudflaredProvider) Name() string {
+	return Cloudflared
+}
+
+func (p *CloudflaredProvider) AgentAddress() string {
+	return p.host
+}
+
+func (p *CloudflaredProvider) Tunnels(ctx context.Context) ([]Tunnel, error) {
+	var resp cloudflaredResponse
+
+	err := getJSON(ctx, p.client, p.endpoint, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	// The hostname stays empty until the tunnel is registered, and for named tunnels
+	if resp.Hostname == "" {
+		return nil, ErrNoTunnels
+	}
+
+	return []Tunnel{{PublicURL: "https://" + resp.Hostname}}, nil
+}
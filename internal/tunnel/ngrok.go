@@ -0,0 +1,70 @@
+package tunnel
+
+import (
+	"context"
+	"net/http"
+)
+
+// NgrokProvider reads tunnels from the ngrok agent API, by default at localhost:4040.
+type NgrokProvider struct {
+	apiURL string
+	host   string
+	client *http.Client
+}
+
+// ngrokResponse is the response of ngrok's /api/tunnels endpoint.
+type ngrokResponse struct {
+	Tunnels []struct {
+		// Name is "command_line" for the tunnel of 'ngrok http', or its name in the config file
+		Name string `json:"name"`
+
+		// PublicURL is the externally accessible URL, e.g. "https://abc123.ngrok.io"
+		PublicURL string `json:"public_url"` //nolint:tagliatelle
+
+		Config struct {
+			// Addr is the local address the tunnel forwards to, e.g. "http://localhost:4000"
+			Addr string `json:"addr"`
+		} `json:"config"`
+	} `json:"tunnels"`
+}
+
+// NewNgrok returns a provider for the ngrok agent API at apiURL, e.g. "http://localhost:4040".
+func NewNgrok(apiURL string) (*NgrokProvider, error) {
+	parsed, err := parseAgentURL(apiURL)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed.Path = "/api/tunnels"
+
+	return &NgrokProvider{apiURL: parsed.String(), host: parsed.Host, client: http.DefaultClient}, nil
+}
+
+func (p *NgrokProvider) Name() string {
+	return Ngrok
+}
+
+func (p *NgrokProvider) AgentAddress() string {
+	return p.host
+}
+
+func (p *NgrokProvider) Tunnels(ctx context.Context) ([]Tunnel, error) {
+	var resp ngrokResponse
+
+	err := getJSON(ctx, p.client, p.apiURL, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Tunnels) == 0 {
+		// ngrok is running but no tunnels are active
+		return nil, ErrNoTunnels
+	}
+
+	tunnels := make([]Tunnel, 0, len(resp.Tunnels))
+	for _, tunnel := range resp.Tunnels {
+		tunnels = append(tunnels, Tunnel{PublicURL: tunnel.PublicURL, Name: tunnel.Name, Addr: tunnel.Config.Addr})
+	}
+
+	return tunnels, nil
+}
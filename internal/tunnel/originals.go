@@ -0,0 +1,168 @@
+package tunnel
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	dirPerm  = 0o700
+	filePerm = 0o600
+)
+
+var ErrInvalidDestinationId = errors.New("invalid destination ID")
+
+// Original is the URL a destination had before a tunnel sync pointed it at a tunnel.
+type Original struct {
+	DestinationId string `json:"destinationId"`
+	Name          string `json:"name,omitempty"`
+	// Project is the --project value the destination was synced under.
+	Project string `json:"project"`
+	// URL is the destination's URL before the first sync.
+	URL string `json:"url"`
+	// TunnelURL is the URL the last sync set, used to tell whether someone changed it since.
+	TunnelURL string    `json:"tunnelUrl"`
+	ChangedAt time.Time `json:"changedAt"`
+}
+
+// Originals remembers the URLs of destinations changed by tunnel syncs, one file per
+// destination, so that they can be put back when the dev session ends.
+type Originals struct {
+	dir string
+}
+
+// NewOriginals returns a store that keeps its entries in dir.
+func NewOriginals(dir string) *Originals {
+	return &Originals{dir: dir}
+}
+
+// DefaultOriginals returns the store in the user's cache directory.
+func DefaultOriginals() (*Originals, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewOriginals(filepath.Join(dir, "ampersand", "tunnel-originals")), nil
+}
+
+// Record notes that a destination is being changed to tunnelURL. If the destination was
+// already changed by an earlier sync, the URL from before that sync is kept.
+func (o *Originals) Record(orig Original) error {
+	path, err := o.path(orig.DestinationId)
+	if err != nil {
+		return err
+	}
+
+	existing, err := o.Get(orig.DestinationId)
+	if err != nil {
+		return err
+	}
+
+	if existing != nil {
+		orig.URL = existing.URL
+	}
+
+	data, err := json.MarshalIndent(&orig, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal original URL: %w", err)
+	}
+
+	err = os.MkdirAll(o.dir, dirPerm)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, filePerm)
+}
+
+// Get returns the recorded original of a destination, or nil if there is none.
+func (o *Originals) Get(destinationId string) (*Original, error) {
+	path, err := o.path(destinationId)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	orig := &Original{}
+
+	err = json.Unmarshal(data, orig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	return orig, nil
+}
+
+// List returns the recorded originals of a project, sorted by destination name.
+func (o *Originals) List(project string) ([]*Original, error) {
+	entries, err := os.ReadDir(o.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var origs []*Original
+
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || entry.IsDir() {
+			continue
+		}
+
+		orig, err := o.Get(id)
+		if err != nil {
+			return nil, err
+		}
+
+		if orig != nil && orig.Project == project {
+			origs = append(origs, orig)
+		}
+	}
+
+	sort.Slice(origs, func(i, j int) bool {
+		return origs[i].Name+origs[i].DestinationId < origs[j].Name+origs[j].DestinationId
+	})
+
+	return origs, nil
+}
+
+// Remove forgets the original of a destination, once it has been restored.
+func (o *Originals) Remove(destinationId string) error {
+	path, err := o.path(destinationId)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+func (o *Originals) path(destinationId string) (string, error) {
+	if destinationId == "" || destinationId != filepath.Base(destinationId) || strings.HasPrefix(destinationId, ".") {
+		return "", fmt.Errorf("%w: %q", ErrInvalidDestinationId, destinationId)
+	}
+
+	return filepath.Join(o.dir, destinationId+".json"), nil
+}
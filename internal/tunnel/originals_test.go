@@ -0,0 +1,67 @@
+package tunnel
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestOriginals(t *testing.T) {
+	t.Parallel()
+
+	originals := NewOriginals(t.TempDir())
+
+	orig, err := originals.Get("d1")
+	if err != nil || orig != nil {
+		t.Fatalf("Get() on empty store = %v, %v, want nil, nil", orig, err)
+	}
+
+	err = originals.Record(Original{
+		DestinationId: "d1", Name: "shared", Project: "proj",
+		URL: "https://team.example.com/hooks", TunnelURL: "https://a.ngrok.io/hooks",
+	})
+	if err != nil {
+		t.Fatalf("Record() returned %v", err)
+	}
+
+	// A second sync must not lose the URL from before the first one
+	err = originals.Record(Original{
+		DestinationId: "d1", Name: "shared", Project: "proj",
+		URL: "https://a.ngrok.io/hooks", TunnelURL: "https://b.ngrok.io/hooks",
+	})
+	if err != nil {
+		t.Fatalf("Record() returned %v", err)
+	}
+
+	err = originals.Record(Original{DestinationId: "d2", Project: "other", URL: "https://x", TunnelURL: "https://y"})
+	if err != nil {
+		t.Fatalf("Record() returned %v", err)
+	}
+
+	list, err := originals.List("proj")
+	if err != nil {
+		t.Fatalf("List() returned %v", err)
+	}
+
+	if len(list) != 1 {
+		t.Fatalf("List() returned %d originals, want 1", len(list))
+	}
+
+	if list[0].URL != "https://team.example.com/hooks" || list[0].TunnelURL != "https://b.ngrok.io/hooks" {
+		t.Errorf("List()[0] = %+v, want the first URL and the last tunnel URL", list[0])
+	}
+
+	err = originals.Remove("d1")
+	if err != nil {
+		t.Fatalf("Remove() returned %v", err)
+	}
+
+	list, err = originals.List("proj")
+	if err != nil || len(list) != 0 {
+		t.Errorf("List() after Remove() = %v, %v, want empty", list, err)
+	}
+
+	err = originals.Record(Original{DestinationId: "../escape"})
+	if !errors.Is(err, ErrInvalidDestinationId) {
+		t.Errorf("Record() with a path as ID returned %v, want ErrInvalidDestinationId", err)
+	}
+}
@@ -0,0 +1,35 @@
+package tunnel
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+)
+
+// StaticProvider is for when the machine is already reachable under a known public URL,
+// e.g. through a tunnel the CLI doesn't know about or a dev server with a public IP.
+type StaticProvider struct {
+	publicURL string
+}
+
+// NewStatic returns a provider that always reports publicURL.
+func NewStatic(publicURL string) (*StaticProvider, error) {
+	parsed, err := url.Parse(publicURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidStaticURL, publicURL)
+	}
+
+	return &StaticProvider{publicURL: publicURL}, nil
+}
+
+func (p *StaticProvider) Name() string {
+	return Static
+}
+
+func (p *StaticProvider) AgentAddress() string {
+	return ""
+}
+
+func (p *StaticProvider) Tunnels(context.Context) ([]Tunnel, error) {
+	return []Tunnel{{PublicURL: p.publicURL}}, nil
+}
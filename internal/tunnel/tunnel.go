@@ -0,0 +1,162 @@
+// Package tunnel discovers the public URL under which a local tunnel agent, such as
+// ngrok or cloudflared, exposes the developer's machine.
+package tunnel
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+var (
+	ErrNoTunnels        = errors.New("no tunnels found")
+	ErrUnexpectedStatus = errors.New("tunnel agent API returned unexpected status code")
+	ErrUnknownProvider  = errors.New("unknown tunnel provider")
+	ErrInvalidAgentURL  = errors.New("invalid tunnel agent address")
+	ErrInvalidStaticURL = errors.New("public URL must be an absolute http or https URL")
+	ErrMissingStaticURL = errors.New("the static provider needs a public URL, please provide --url")
+	ErrInvalidProtocol  = errors.New("invalid protocol: must be 'http' or 'https'")
+)
+
+// Provider finds the public URLs of the tunnels served by a local agent.
+type Provider interface {
+	// Name identifies the provider in messages, e.g. "ngrok".
+	Name() string
+
+	// AgentAddress is the host:port of the local agent to wait for before asking
+	// for URLs, or empty if there is nothing to wait for.
+	AgentAddress() string
+
+	// Tunnels returns all active tunnels.
+	Tunnels(ctx context.Context) ([]Tunnel, error)
+}
+
+// Tunnel is an active tunnel of an agent.
+type Tunnel struct {
+	// PublicURL is the externally accessible URL, e.g. "https://abc123.ngrok.io"
+	PublicURL string
+
+	// Name and Addr identify the tunnel when the agent has several: its name and the local
+	// address it forwards to. Unlike the public URL, they survive restarts of the agent.
+	Name string
+	Addr string
+}
+
+// Find returns the tunnel of tunnels that is the same as t, which may have a new public URL
+// since the agent restarted. It reports false if there is no such tunnel, or several.
+func (t Tunnel) Find(tunnels []Tunnel) (Tunnel, bool) {
+	var found []Tunnel
+
+	for _, candidate := range tunnels {
+		if candidate.PublicURL == t.PublicURL {
+			return candidate, true
+		}
+
+		if candidate.Name == t.Name && candidate.Addr == t.Addr {
+			found = append(found, candidate)
+		}
+	}
+
+	if len(found) != 1 {
+		return Tunnel{}, false
+	}
+
+	return found[0], true
+}
+
+// PublicURLs returns the public URLs of tunnels.
+func PublicURLs(tunnels []Tunnel) []string {
+	urls := make([]string, 0, len(tunnels))
+	for _, tunnel := range tunnels {
+		urls = append(urls, tunnel.PublicURL)
+	}
+
+	return urls
+}
+
+// Names of the built-in providers.
+const (
+	Ngrok       = "ngrok"
+	Cloudflared = "cloudflared"
+	Static      = "static"
+)
+
+// Config holds the settings of all built-in providers; each uses only its own.
+type Config struct {
+	// NgrokAddress is the host:port of the ngrok agent API.
+	NgrokAddress string
+	// NgrokProtocol is the protocol used to reach the ngrok agent API, http or https.
+	NgrokProtocol string
+	// CloudflaredMetrics is the host:port of cloudflared's metrics server.
+	CloudflaredMetrics string
+	// StaticURL is the public URL used by the static provider.
+	StaticURL string
+}
+
+// New returns the built-in provider with the given name.
+func New(name string, config Config) (Provider, error) {
+	switch name {
+	case Ngrok:
+		if config.NgrokProtocol != "http" && config.NgrokProtocol != "https" {
+			return nil, fmt.Errorf("%w: '%s'", ErrInvalidProtocol, config.NgrokProtocol)
+		}
+
+		return NewNgrok(config.NgrokProtocol + "://" + config.NgrokAddress)
+	case Cloudflared:
+		return NewCloudflared("http://" + config.CloudflaredMetrics)
+	case Static:
+		if config.StaticURL == "" {
+			return nil, ErrMissingStaticURL
+		}
+
+		return NewStatic(config.StaticURL)
+	default:
+		return nil, fmt.Errorf("%w: %q (use %s, %s or %s)", ErrUnknownProvider, name, Ngrok, Cloudflared, Static)
+	}
+}
+
+// parseAgentURL parses the base URL of a local agent's API.
+func parseAgentURL(raw string) (*url.URL, error) {
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Host == "" {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidAgentURL, raw)
+	}
+
+	return parsed, nil
+}
+
+// getJSON fetches a JSON document from a local agent's API into out.
+func getJSON(ctx context.Context, client *http.Client, endpoint string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create API request: %w", err)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		// This error typically means the agent is not running or not accessible
+		return fmt.Errorf("failed to connect to API: %w", err)
+	}
+
+	defer func() {
+		closeErr := resp.Body.Close()
+		if closeErr != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "error closing response body: %v\n", closeErr)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w %d", ErrUnexpectedStatus, resp.StatusCode)
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(out)
+	if err != nil {
+		return fmt.Errorf("failed to parse API response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,224 @@
+package tunnel
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// agent stands in for a tunnel agent's local API, answering path with status and body.
+func agent(t *testing.T, path string, status int, body string) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != path {
+			http.NotFound(w, r)
+
+			return
+		}
+
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+func TestNgrok(t *testing.T) {
+	t.Parallel()
+
+	srv := agent(t, "/api/tunnels", http.StatusOK,
+		`{"tunnels":[{"name":"web","public_url":"https://a.ngrok.io","config":{"addr":"http://localhost:4000"}},`+
+			`{"name":"api","public_url":"https://b.ngrok.io","config":{"addr":"http://localhost:5000"}}]}`)
+
+	provider, err := New(Ngrok, Config{NgrokAddress: strings.TrimPrefix(srv.URL, "http://"), NgrokProtocol: "http"})
+	if err != nil {
+		t.Fatalf("New() returned %v", err)
+	}
+
+	if provider.AgentAddress() != srv.Listener.Addr().String() {
+		t.Errorf("AgentAddress() = %q, want %q", provider.AgentAddress(), srv.Listener.Addr())
+	}
+
+	tunnels, err := provider.Tunnels(t.Context())
+	if err != nil {
+		t.Fatalf("Tunnels() returned %v", err)
+	}
+
+	want := []Tunnel{
+		{PublicURL: "https://a.ngrok.io", Name: "web", Addr: "http://localhost:4000"},
+		{PublicURL: "https://b.ngrok.io", Name: "api", Addr: "http://localhost:5000"},
+	}
+	if !slices.Equal(tunnels, want) {
+		t.Errorf("Tunnels() = %v, want %v", tunnels, want)
+	}
+}
+
+func TestCloudflared(t *testing.T) {
+	t.Parallel()
+
+	srv := agent(t, "/quicktunnel", http.StatusOK, `{"hostname":"quick-brown-fox.trycloudflare.com"}`)
+
+	provider, err := New(Cloudflared, Config{CloudflaredMetrics: strings.TrimPrefix(srv.URL, "http://")})
+	if err != nil {
+		t.Fatalf("New() returned %v", err)
+	}
+
+	tunnels, err := provider.Tunnels(t.Context())
+	if err != nil {
+		t.Fatalf("Tunnels() returned %v", err)
+	}
+
+	want := []string{"https://quick-brown-fox.trycloudflare.com"}
+	if urls := PublicURLs(tunnels); !slices.Equal(urls, want) {
+		t.Errorf("Tunnels() = %v, want %v", urls, want)
+	}
+}
+
+func TestNoTunnels(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		provider func(url string) (Provider, error)
+		path     string
+		status   int
+		body     string
+		wantErr  error
+	}{
+		{
+			name:     "ngrok without tunnels",
+			provider: func(url string) (Provider, error) { return NewNgrok(url) },
+			path:     "/api/tunnels",
+			status:   http.StatusOK,
+			body:     `{"tunnels":[]}`,
+			wantErr:  ErrNoTunnels,
+		},
+		{
+			name:     "cloudflared named tunnel",
+			provider: func(url string) (Provider, error) { return NewCloudflared(url) },
+			path:     "/quicktunnel",
+			status:   http.StatusOK,
+			body:     `{"hostname":""}`,
+			wantErr:  ErrNoTunnels,
+		},
+		{
+			name:     "ngrok error status",
+			provider: func(url string) (Provider, error) { return NewNgrok(url) },
+			path:     "/api/tunnels",
+			status:   http.StatusBadGateway,
+			wantErr:  ErrUnexpectedStatus,
+		},
+		{
+			name:     "cloudflared error status",
+			provider: func(url string) (Provider, error) { return NewCloudflared(url) },
+			path:     "/quicktunnel",
+			status:   http.StatusNotFound,
+			wantErr:  ErrUnexpectedStatus,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			srv := agent(t, tt.path, tt.status, tt.body)
+
+			provider, err := tt.provider(srv.URL)
+			if err != nil {
+				t.Fatalf("failed to create provider: %v", err)
+			}
+
+			_, err = provider.Tunnels(t.Context())
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Tunnels() returned %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStatic(t *testing.T) {
+	t.Parallel()
+
+	provider, err := New(Static, Config{StaticURL: "https://dev.example.com:8443"})
+	if err != nil {
+		t.Fatalf("New() returned %v", err)
+	}
+
+	if provider.AgentAddress() != "" {
+		t.Errorf("AgentAddress() = %q, want empty", provider.AgentAddress())
+	}
+
+	tunnels, err := provider.Tunnels(t.Context())
+	if urls := PublicURLs(tunnels); err != nil || !slices.Equal(urls, []string{"https://dev.example.com:8443"}) {
+		t.Errorf("Tunnels() = %v, %v", urls, err)
+	}
+
+	for _, raw := range []string{"dev.example.com", "ftp://dev.example.com", "https://"} {
+		_, err := NewStatic(raw)
+		if !errors.Is(err, ErrInvalidStaticURL) {
+			t.Errorf("NewStatic(%q) returned %v, want ErrInvalidStaticURL", raw, err)
+		}
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		config  Config
+		wantErr error
+	}{
+		{name: "localtunnel", wantErr: ErrUnknownProvider},
+		{name: Ngrok, config: Config{NgrokAddress: "localhost:4040", NgrokProtocol: "ftp"}, wantErr: ErrInvalidProtocol},
+		{name: Static, wantErr: ErrMissingStaticURL},
+		{name: Cloudflared, config: Config{CloudflaredMetrics: ""}, wantErr: ErrInvalidAgentURL},
+	}
+
+	for _, tt := range tests {
+		_, err := New(tt.name, tt.config)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("New(%q) returned %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestTunnelFind(t *testing.T) {
+	t.Parallel()
+
+	chosen := Tunnel{PublicURL: "https://a.ngrok.io", Name: "web", Addr: "http://localhost:4000"}
+
+	// After a restart of the agent, the tunnel is found by its name and address
+	restarted := []Tunnel{
+		{PublicURL: "https://c.ngrok.io", Name: "api", Addr: "http://localhost:5000"},
+		{PublicURL: "https://d.ngrok.io", Name: "web", Addr: "http://localhost:4000"},
+	}
+
+	found, ok := chosen.Find(restarted)
+	if !ok || found.PublicURL != "https://d.ngrok.io" {
+		t.Errorf("Find() = %v, %v, want the restarted web tunnel", found, ok)
+	}
+
+	// A tunnel that is gone, or can't be told apart from another, isn't found
+	for _, tunnels := range [][]Tunnel{
+		restarted[:1],
+		{restarted[1], {PublicURL: "https://e.ngrok.io", Name: "web", Addr: "http://localhost:4000"}},
+	} {
+		if found, ok := chosen.Find(tunnels); ok {
+			t.Errorf("Find(%v) = %v, want not found", tunnels, found)
+		}
+	}
+
+	// The only tunnel of an agent without names is always the same one
+	quick := Tunnel{PublicURL: "https://old.trycloudflare.com"}
+
+	found, ok = quick.Find([]Tunnel{{PublicURL: "https://new.trycloudflare.com"}})
+	if !ok || found.PublicURL != "https://new.trycloudflare.com" {
+		t.Errorf("Find() = %v, %v, want the new quick tunnel", found, ok)
+	}
+}
@@ -0,0 +1,249 @@
+package webhook
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+var (
+	ErrInvalidCAFile  = errors.New("no certificates found in CA file")
+	errUnsupportedKey = errors.New("unsupported private key type")
+)
+
+const (
+	caValidity   = 10 * 365 * 24 * time.Hour
+	leafValidity = 397 * 24 * time.Hour // the longest lifetime browsers and macOS accept
+	renewBefore  = 30 * 24 * time.Hour
+	serialBits   = 128
+)
+
+// Certs are the files of a local certificate authority and of a server certificate
+// issued by it for the loopback addresses. Trusting CAFile is enough to talk to a
+// listener over HTTPS, and keeps working when the server certificate is renewed.
+type Certs struct {
+	CAFile   string
+	CertFile string
+	KeyFile  string
+}
+
+// DefaultCertsDir returns the directory in the user's cache directory where the
+// local CA and server certificate are kept.
+func DefaultCertsDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "ampersand", "tls"), nil
+}
+
+// EnsureCerts returns the certificates cached in dir, generating the CA and the server
+// certificate if they are missing, about to expire, or don't cover hosts. The server
+// certificate always covers localhost, 127.0.0.1 and ::1. Listeners starting together
+// take turns, so that none of them overwrites the CA that another one's certificate uses.
+func EnsureCerts(ctx context.Context, dir string, hosts ...string) (*Certs, error) {
+	certs := &Certs{
+		CAFile:   filepath.Join(dir, "ca.pem"),
+		CertFile: filepath.Join(dir, "cert.pem"),
+		KeyFile:  filepath.Join(dir, "key.pem"),
+	}
+	caKeyFile := filepath.Join(dir, "ca-key.pem")
+
+	err := os.MkdirAll(dir, dirPerm)
+	if err != nil {
+		return nil, err
+	}
+
+	unlock, err := lockFile(ctx, filepath.Join(dir, "certs"+lockFileExt))
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock %s: %w", dir, err)
+	}
+	defer unlock()
+
+	caCert, caKey, err := loadKeyPair(certs.CAFile, caKeyFile)
+	if err != nil || time.Until(caCert.NotAfter) < renewBefore {
+		caCert, caKey, err = createCA(certs.CAFile, caKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create local CA: %w", err)
+		}
+	}
+
+	hosts = append([]string{"localhost", "127.0.0.1", "::1"}, hosts...)
+
+	leaf, _, err := loadKeyPair(certs.CertFile, certs.KeyFile)
+	if err == nil && time.Until(leaf.NotAfter) > renewBefore &&
+		leaf.CheckSignatureFrom(caCert) == nil && coversHosts(leaf, hosts) {
+		return certs, nil
+	}
+
+	err = createLeaf(certs.CertFile, certs.KeyFile, hosts, caCert, caKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create server certificate: %w", err)
+	}
+
+	return certs, nil
+}
+
+// LoadCertPool returns the system's trusted certificates plus those in caFile.
+func LoadCertPool(caFile string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCAFile, caFile)
+	}
+
+	return pool, nil
+}
+
+// ClientTLSConfig returns a TLS configuration that trusts the certificates in caFile
+// in addition to the system's.
+func ClientTLSConfig(caFile string) (*tls.Config, error) {
+	pool, err := LoadCertPool(caFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}, nil
+}
+
+func coversHosts(cert *x509.Certificate, hosts []string) bool {
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			if !slices.ContainsFunc(cert.IPAddresses, ip.Equal) {
+				return false
+			}
+		} else if !slices.Contains(cert.DNSNames, host) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func createCA(certFile, keyFile string) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), serialBits))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{Organization: []string{"Ampersand CLI"}, CommonName: "amp listen local CA"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(caValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = writeKeyPair(certFile, keyFile, der, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cert, key, nil
+}
+
+func createLeaf(certFile, keyFile string, hosts []string, ca *x509.Certificate, caKey *ecdsa.PrivateKey) error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), serialBits))
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{Organization: []string{"Ampersand CLI"}, CommonName: "amp listen"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(leafValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		return err
+	}
+
+	return writeKeyPair(certFile, keyFile, der, key)
+}
+
+func writeKeyPair(certFile, keyFile string, der []byte, key *ecdsa.PrivateKey) error {
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+
+	err = writeFileAtomic(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}))
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func loadKeyPair(certFile, keyFile string) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	pair, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	key, ok := pair.PrivateKey.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, nil, fmt.Errorf("%w in %s", errUnsupportedKey, keyFile)
+	}
+
+	return pair.Leaf, key, nil
+}
@@ -0,0 +1,140 @@
+package webhook
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestEnsureCerts(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	certs, err := EnsureCerts(t.Context(), dir)
+	if err != nil {
+		t.Fatalf("EnsureCerts() returned error: %v", err)
+	}
+
+	pool, err := LoadCertPool(certs.CAFile)
+	if err != nil {
+		t.Fatalf("LoadCertPool() returned error: %v", err)
+	}
+
+	leaf := loadLeaf(t, certs)
+
+	for _, host := range []string{"localhost", "127.0.0.1", "::1"} {
+		_, err := leaf.Verify(x509.VerifyOptions{DNSName: host, Roots: pool})
+		if err != nil {
+			t.Fatalf("certificate is not valid for %s: %v", host, err)
+		}
+	}
+
+	before, err := os.ReadFile(certs.CertFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The cached certificate is reused as long as it covers the requested hosts.
+	_, err = EnsureCerts(t.Context(), dir)
+	if err != nil {
+		t.Fatalf("EnsureCerts() returned error: %v", err)
+	}
+
+	after, err := os.ReadFile(certs.CertFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(before, after) {
+		t.Fatal("EnsureCerts() regenerated a valid certificate")
+	}
+
+	// A new host triggers a new certificate from the same CA.
+	_, err = EnsureCerts(t.Context(), dir, "192.168.1.10")
+	if err != nil {
+		t.Fatalf("EnsureCerts() returned error: %v", err)
+	}
+
+	_, err = loadLeaf(t, certs).Verify(x509.VerifyOptions{DNSName: "192.168.1.10", Roots: pool})
+	if err != nil {
+		t.Fatalf("certificate is not valid for the added host: %v", err)
+	}
+}
+
+func TestEnsureCertsConcurrently(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	var (
+		wg  sync.WaitGroup
+		mu  sync.Mutex
+		cas = map[string]bool{}
+	)
+
+	// Listeners starting together must all end up with the same CA.
+	start := make(chan struct{})
+
+	for range 8 {
+		wg.Go(func() {
+			<-start
+
+			certs, err := EnsureCerts(t.Context(), dir)
+			if err != nil {
+				t.Errorf("EnsureCerts() returned error: %v", err)
+
+				return
+			}
+
+			data, err := os.ReadFile(certs.CAFile)
+			if err != nil {
+				t.Error(err)
+
+				return
+			}
+
+			mu.Lock()
+			cas[string(data)] = true
+			mu.Unlock()
+		})
+	}
+
+	close(start)
+	wg.Wait()
+
+	if len(cas) != 1 {
+		t.Fatalf("EnsureCerts() created %d CAs, want 1", len(cas))
+	}
+
+	certs := &Certs{
+		CAFile:   filepath.Join(dir, "ca.pem"),
+		CertFile: filepath.Join(dir, "cert.pem"),
+		KeyFile:  filepath.Join(dir, "key.pem"),
+	}
+
+	pool, err := LoadCertPool(certs.CAFile)
+	if err != nil {
+		t.Fatalf("LoadCertPool() returned error: %v", err)
+	}
+
+	_, err = loadLeaf(t, certs).Verify(x509.VerifyOptions{DNSName: "localhost", Roots: pool})
+	if err != nil {
+		t.Fatalf("certificate is not signed by the CA: %v", err)
+	}
+}
+
+func loadLeaf(t *testing.T, certs *Certs) *x509.Certificate {
+	t.Helper()
+
+	pair, err := tls.LoadX509KeyPair(certs.CertFile, certs.KeyFile)
+	if err != nil {
+		t.Fatalf("failed to load server certificate: %v", err)
+	}
+
+	return pair.Leaf
+}
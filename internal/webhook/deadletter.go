@@ -0,0 +1,125 @@
+package webhook
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// DeadLetter is an event that could not be delivered to one of its targets.
+type DeadLetter struct {
+	// Event is the queue's own copy of the event, which redelivery updates, so that the
+	// listener can keep reading the one it received while it is being redelivered.
+	Event  *Event
+	Target *Target
+}
+
+// DeadLetterQueue buffers undelivered events in memory so they can be
+// redelivered, in the order they were received, once their target is back.
+type DeadLetterQueue struct {
+	mu      sync.Mutex
+	items   []DeadLetter
+	maxSize int
+}
+
+// NewDeadLetterQueue returns a queue holding at most maxSize events.
+// When full, the oldest event is dropped to make room.
+func NewDeadLetterQueue(maxSize int) *DeadLetterQueue {
+	return &DeadLetterQueue{maxSize: maxSize}
+}
+
+// Push adds an event that could not be delivered to the given targets to the queue. The
+// queue keeps a copy of the event, shared by its targets, so the caller remains free to
+// use its own. It returns the events that were dropped to make room, if any.
+func (q *DeadLetterQueue) Push(evt *Event, targets ...*Target) []DeadLetter {
+	if len(targets) == 0 {
+		return nil
+	}
+
+	cp := evt.Clone()
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, target := range targets {
+		q.items = append(q.items, DeadLetter{Event: cp, Target: target})
+	}
+
+	var dropped []DeadLetter
+
+	if q.maxSize > 0 && len(q.items) > q.maxSize {
+		excess := len(q.items) - q.maxSize
+		dropped = slices.Clone(q.items[:excess])
+		q.items = q.items[excess:]
+	}
+
+	return dropped
+}
+
+// Len returns the number of events waiting for redelivery.
+func (q *DeadLetterQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.items)
+}
+
+// Run attempts redelivery every interval until ctx is done, calling onDelivery for
+// each event that finally reaches its target, once its forward result is updated.
+func (q *DeadLetterQueue) Run(ctx context.Context, interval time.Duration, onDelivery func(DeadLetter, *Delivery)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			q.redeliver(ctx, onDelivery)
+		}
+	}
+}
+
+// redeliver makes one pass over the queue. Once a target fails, later events for
+// the same target are left alone so that they stay in order.
+func (q *DeadLetterQueue) redeliver(ctx context.Context, onDelivery func(DeadLetter, *Delivery)) {
+	q.mu.Lock()
+	pending := make([]DeadLetter, len(q.items))
+	copy(pending, q.items)
+	q.mu.Unlock()
+
+	down := make(map[string]bool)
+
+	for _, item := range pending {
+		if down[item.Target.URL] {
+			continue
+		}
+
+		delivery := Deliver(ctx, item.Target, item.Event, RetryPolicy{})
+		if !delivery.Delivered() {
+			down[item.Target.URL] = true
+
+			continue
+		}
+
+		delivery.Result.Redelivered = true
+		item.Event.replaceForward(delivery.Result)
+
+		q.remove(item)
+		onDelivery(item, delivery)
+	}
+}
+
+func (q *DeadLetterQueue) remove(item DeadLetter) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for idx, candidate := range q.items {
+		if candidate.Event == item.Event && candidate.Target == item.Target {
+			q.items = append(q.items[:idx], q.items[idx+1:]...)
+
+			return
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package webhook
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Candidate JSON paths, in order of preference, for summarizing a payload. They cover
+// Ampersand's own deliveries as well as the provider-style fixtures used by `amp trigger`.
+var ( //nolint:gochecknoglobals
+	eventTypePaths = []string{"type", "eventType", "event", "action"}
+	objectPaths    = []string{"objectName", "result.0.objectName", "data.object.object", "object"}
+)
+
+// Describe extracts a short event type and object name from a webhook body, for
+// display purposes. Either may be empty if the payload doesn't say.
+func Describe(body []byte) (eventType, object string) {
+	var payload any
+
+	err := json.Unmarshal(body, &payload)
+	if err != nil {
+		return "", ""
+	}
+
+	return firstJSON(payload, eventTypePaths), firstJSON(payload, objectPaths)
+}
+
+func firstJSON(payload any, paths []string) string {
+	for _, path := range paths {
+		value, ok := lookupJSON(payload, strings.Split(path, "."))
+		if ok && value != "" && value != "null" {
+			return value
+		}
+	}
+
+	return ""
+}
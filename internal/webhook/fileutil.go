@@ -0,0 +1,67 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// lockFile takes a lock shared by amp processes, which is a file that only one process can
+// create. It waits for the lock to be released, and breaks locks left behind by crashed
+// processes. The returned function releases the lock.
+func lockFile(ctx context.Context, path string) (func(), error) {
+	const (
+		retryInterval = 10 * time.Millisecond
+		staleAfter    = 10 * time.Second
+	)
+
+	for {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, filePerm)
+		if err == nil {
+			_ = file.Close()
+
+			return func() { _ = os.Remove(path) }, nil
+		}
+
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, err
+		}
+
+		info, err := os.Stat(path)
+		if err == nil && time.Since(info.ModTime()) > staleAfter {
+			_ = os.Remove(path)
+
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(retryInterval):
+		}
+	}
+}
+
+// writeFileAtomic replaces the file in one go, so that readers never see a partial file.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".amp-*"+filepath.Ext(path))
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,158 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Response is the application's reply to a forwarded webhook.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// Forward sends a webhook body and its headers to target and reads back the full response.
+func Forward(
+	ctx context.Context, client *http.Client, target string, header http.Header, body []byte,
+) (*Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("error creating forward request: %w", err)
+	}
+
+	// Copy headers
+	for k, v := range header {
+		if k == "Content-Length" {
+			continue
+		}
+
+		for _, vv := range v {
+			req.Header.Add(k, vv)
+		}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response: %w", err)
+	}
+
+	return &Response{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       respBody,
+	}, nil
+}
+
+// Result summarizes the outcome of forwarding to target, for recording alongside the event.
+// Exactly one of resp and err is expected to be non-nil.
+func Result(target string, resp *Response, latency time.Duration, err error) *ForwardResult {
+	result := &ForwardResult{
+		URL:      target,
+		Latency:  latency,
+		Attempts: 1,
+	}
+
+	if err != nil {
+		result.Error = err.Error()
+
+		return result
+	}
+
+	result.StatusCode = resp.StatusCode
+
+	return result
+}
+
+// RetryPolicy controls how often forwarding to an unavailable target is retried.
+// The delay between attempts starts at Backoff and doubles up to MaxBackoff.
+type RetryPolicy struct {
+	Retries    int
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+// Delivery is the outcome of forwarding an event to a single target.
+// Exactly one of Response and Err is set.
+type Delivery struct {
+	Target   *Target
+	Response *Response
+	Err      error
+	Result   *ForwardResult
+}
+
+// Delivered reports whether the target was reachable and accepted the event for processing.
+func (d *Delivery) Delivered() bool {
+	return d.Result.Delivered()
+}
+
+func isUnavailableStatus(code int) bool {
+	return code == http.StatusBadGateway ||
+		code == http.StatusServiceUnavailable ||
+		code == http.StatusGatewayTimeout
+}
+
+// Deliver forwards the event to the target, retrying according to the policy
+// for as long as the target is unreachable or reports itself unavailable.
+func Deliver(ctx context.Context, target *Target, evt *Event, retry RetryPolicy) *Delivery {
+	client := &http.Client{Timeout: target.Timeout, Transport: target.Transport}
+	delay := retry.Backoff
+
+	for attempt := 1; ; attempt++ {
+		start := time.Now()
+		resp, err := Forward(ctx, client, target.URL, evt.Headers, evt.Body)
+
+		delivery := &Delivery{
+			Target:   target,
+			Response: resp,
+			Err:      err,
+			Result:   Result(target.URL, resp, time.Since(start), err),
+		}
+		delivery.Result.Attempts = attempt
+
+		if delivery.Delivered() || attempt > retry.Retries {
+			return delivery
+		}
+
+		select {
+		case <-ctx.Done():
+			return delivery
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+		if retry.MaxBackoff > 0 && delay > retry.MaxBackoff {
+			delay = retry.MaxBackoff
+		}
+	}
+}
+
+// ForwardAll delivers the event to every target concurrently, each with its own timeout,
+// and returns one delivery per target in the same order as targets.
+func ForwardAll(ctx context.Context, targets []*Target, evt *Event, retry RetryPolicy) []*Delivery {
+	deliveries := make([]*Delivery, len(targets))
+
+	var wg sync.WaitGroup
+
+	for idx, target := range targets {
+		wg.Go(func() {
+			deliveries[idx] = Deliver(ctx, target, evt, retry)
+		})
+	}
+
+	wg.Wait()
+
+	return deliveries
+}
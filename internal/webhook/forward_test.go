@@ -0,0 +1,122 @@
+package webhook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDeliverRetriesUnavailableTarget(t *testing.T) {
+	t.Parallel()
+
+	var calls atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	target := &Target{URL: srv.URL, Timeout: time.Second}
+	evt := &Event{Id: "evt_1", Headers: http.Header{}, Body: []byte(`{}`)}
+
+	delivery := Deliver(context.Background(), target, evt, RetryPolicy{Retries: 1, Backoff: time.Millisecond})
+	if delivery.Delivered() || delivery.Result.Attempts != 2 {
+		t.Fatalf("with 1 retry: delivered=%v attempts=%d, want not delivered after 2 attempts",
+			delivery.Delivered(), delivery.Result.Attempts)
+	}
+
+	delivery = Deliver(context.Background(), target, evt, RetryPolicy{Retries: 5, Backoff: time.Millisecond})
+	if !delivery.Delivered() || delivery.Result.StatusCode != http.StatusAccepted || delivery.Result.Attempts != 1 {
+		t.Fatalf("with 5 retries: delivered=%v status=%d attempts=%d, want 202 on first attempt",
+			delivery.Delivered(), delivery.Result.StatusCode, delivery.Result.Attempts)
+	}
+}
+
+func TestDeadLetterQueueDropsOldest(t *testing.T) {
+	t.Parallel()
+
+	queue := NewDeadLetterQueue(2)
+	target := &Target{URL: "http://127.0.0.1:1"}
+
+	first := &Event{Id: "evt_1"}
+
+	if queue.Push(first, target) != nil || queue.Push(&Event{Id: "evt_2"}, target) != nil {
+		t.Fatal("Push() dropped an event before the queue was full")
+	}
+
+	dropped := queue.Push(&Event{Id: "evt_3"}, target)
+	if len(dropped) != 1 || dropped[0].Event.Id != first.Id {
+		t.Fatalf("Push() on a full queue dropped %v, want the oldest event", dropped)
+	}
+
+	if queue.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", queue.Len())
+	}
+}
+
+func TestDeadLetterQueueRedeliversCopy(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	target := &Target{URL: srv.URL, Timeout: time.Second}
+	evt := &Event{
+		Id:       "evt_1",
+		Headers:  http.Header{},
+		Body:     []byte(`{}`),
+		Forwards: []*ForwardResult{{URL: srv.URL, Error: "connection refused"}},
+	}
+
+	queue := NewDeadLetterQueue(1)
+	queue.Push(evt, target)
+
+	// The listener keeps reading its event, e.g. to show it, while it is being redelivered
+	done := make(chan struct{})
+	reading := make(chan struct{})
+
+	go func() {
+		defer close(reading)
+
+		for {
+			select {
+			case <-done:
+				return
+			default:
+				_ = evt.Clone().Forwards[0].Delivered()
+			}
+		}
+	}()
+
+	var redelivered *Event
+
+	queue.redeliver(context.Background(), func(item DeadLetter, _ *Delivery) {
+		redelivered = item.Event
+	})
+
+	close(done)
+	<-reading
+
+	if redelivered == nil || !redelivered.Forwards[0].Delivered() || !redelivered.Forwards[0].Redelivered {
+		t.Fatalf("redelivered event has forwards %+v, want the redelivery recorded", redelivered)
+	}
+
+	if evt.Forwards[0].Delivered() {
+		t.Error("redelivery updated the event that was pushed, want the queue's copy updated")
+	}
+
+	if queue.Len() != 0 {
+		t.Errorf("Len() = %d after redelivery, want 0", queue.Len())
+	}
+}
@@ -0,0 +1,372 @@
+package webhook
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Roles of the entries written by HARRecorder, in the entries' _ampRole field.
+const (
+	HARReceived  = "received"
+	HARForwarded = "forwarded"
+)
+
+// harBase64 is the encoding of bodies that aren't UTF-8 text.
+const harBase64 = "base64"
+
+var ErrHAREncoding = errors.New("unsupported HAR body encoding")
+
+// HAR is an HTTP Archive, see http://www.softwareishard.com/blog/har-12-spec/.
+type HAR struct {
+	Log HARLog `json:"log"`
+}
+
+type HARLog struct {
+	Version string      `json:"version"`
+	Creator HARCreator  `json:"creator"`
+	Entries []*HAREntry `json:"entries"`
+}
+
+type HARCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type HAREntry struct {
+	StartedDateTime time.Time   `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         HARRequest  `json:"request"`
+	Response        HARResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         HARTimings  `json:"timings"`
+	Comment         string      `json:"comment,omitempty"`
+
+	// Role tells entries received by amp listen apart from the copies it forwarded.
+	Role string `json:"_ampRole,omitempty"`
+	// EventId is the ID under which amp listen recorded the event.
+	EventId string `json:"_ampEventId,omitempty"`
+}
+
+type HARRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []HARNameValue `json:"cookies"`
+	Headers     []HARNameValue `json:"headers"`
+	QueryString []HARNameValue `json:"queryString"`
+	PostData    *HARPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type HARResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []HARNameValue `json:"cookies"`
+	Headers     []HARNameValue `json:"headers"`
+	Content     HARContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type HARNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type HARPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+	// Encoding is "base64" for binary bodies, as for response content. HAR 1.2 only defines
+	// it for responses, but tools that write binary request bodies use it the same way.
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type HARContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type HARTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// NewHAREntry describes one request/response pair. resp may be nil if no response was
+// received, in which case err says why.
+func NewHAREntry(started time.Time, elapsed time.Duration, req HARRequest, resp *Response, err error) *HAREntry {
+	millis := float64(elapsed.Microseconds()) / 1000 //nolint:mnd
+
+	entry := &HAREntry{
+		StartedDateTime: started,
+		Time:            millis,
+		Request:         req,
+		Timings:         HARTimings{Wait: millis},
+		Response: HARResponse{
+			HTTPVersion: "HTTP/1.1",
+			Cookies:     []HARNameValue{},
+			Headers:     []HARNameValue{},
+			HeadersSize: -1,
+		},
+	}
+
+	if resp == nil {
+		if err != nil {
+			entry.Comment = "no response: " + err.Error()
+		}
+
+		return entry
+	}
+
+	entry.Response.Status = resp.StatusCode
+	entry.Response.StatusText = http.StatusText(resp.StatusCode)
+	entry.Response.Headers = harHeaders(resp.Header)
+	entry.Response.BodySize = len(resp.Body)
+	entry.Response.Content = HARContent{
+		Size:     len(resp.Body),
+		MimeType: resp.Header.Get("Content-Type"),
+	}
+	entry.Response.Content.Text, entry.Response.Content.Encoding = harText(resp.Body)
+
+	return entry
+}
+
+// NewHARRequest describes a request for a HAR entry.
+func NewHARRequest(method, url string, header http.Header, body []byte) HARRequest {
+	req := HARRequest{
+		Method:      method,
+		URL:         url,
+		HTTPVersion: "HTTP/1.1",
+		Cookies:     []HARNameValue{},
+		Headers:     harHeaders(header),
+		QueryString: []HARNameValue{},
+		HeadersSize: -1,
+		BodySize:    len(body),
+	}
+
+	if len(body) > 0 {
+		req.PostData = &HARPostData{MimeType: header.Get("Content-Type")}
+		req.PostData.Text, req.PostData.Encoding = harText(body)
+	}
+
+	return req
+}
+
+// Header returns the request headers as an http.Header.
+func (r *HARRequest) Header() http.Header {
+	header := http.Header{}
+	for _, pair := range r.Headers {
+		header.Add(pair.Name, pair.Value)
+	}
+
+	return header
+}
+
+// Body returns the request body.
+func (r *HARRequest) Body() []byte {
+	if r.PostData == nil {
+		return nil
+	}
+
+	return []byte(r.PostData.Text)
+}
+
+// decodeBody replaces a base64 body with the bytes it encodes.
+func (r *HARRequest) decodeBody() error {
+	if r.PostData == nil {
+		return nil
+	}
+
+	switch r.PostData.Encoding {
+	case "":
+		return nil
+	case harBase64:
+		body, err := base64.StdEncoding.DecodeString(r.PostData.Text)
+		if err != nil {
+			return fmt.Errorf("failed to decode request body: %w", err)
+		}
+
+		r.PostData.Text, r.PostData.Encoding = string(body), ""
+
+		return nil
+	default:
+		return fmt.Errorf("%w %q", ErrHAREncoding, r.PostData.Encoding)
+	}
+}
+
+// harText returns a body as HAR text: as it is if it is UTF-8, or else encoded as base64.
+func harText(body []byte) (string, string) {
+	if utf8.Valid(body) {
+		return string(body), ""
+	}
+
+	return base64.StdEncoding.EncodeToString(body), harBase64
+}
+
+func harHeaders(header http.Header) []HARNameValue {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	pairs := make([]HARNameValue, 0, len(names))
+
+	for _, name := range names {
+		for _, value := range header[name] {
+			pairs = append(pairs, HARNameValue{Name: name, Value: value})
+		}
+	}
+
+	return pairs
+}
+
+// HARRecorder collects entries and writes them to a HAR file, when flushed. Rewriting
+// the whole file for every entry would get slow in long sessions, so Run flushes it
+// periodically instead, and it is flushed one last time when the listener stops.
+type HARRecorder struct {
+	path  string
+	mu    sync.Mutex
+	har   HAR
+	dirty bool
+}
+
+// NewHARRecorder creates the HAR file at path, overwriting any existing file.
+func NewHARRecorder(path, version string) (*HARRecorder, error) {
+	recorder := &HARRecorder{
+		path: path,
+		har: HAR{Log: HARLog{
+			Version: "1.2",
+			Creator: HARCreator{Name: "amp", Version: version},
+			Entries: []*HAREntry{},
+		}},
+	}
+
+	return recorder, recorder.write()
+}
+
+// Add records an entry, which is written on the next flush. Entries are kept in the order
+// they started.
+func (r *HARRecorder) Add(entry *HAREntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries := r.har.Log.Entries
+	idx := sort.Search(len(entries), func(i int) bool {
+		return entries[i].StartedDateTime.After(entry.StartedDateTime)
+	})
+
+	r.har.Log.Entries = append(entries[:idx], append([]*HAREntry{entry}, entries[idx:]...)...)
+	r.dirty = true
+}
+
+// Flush writes the file if entries were added since it was last written.
+func (r *HARRecorder) Flush() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.dirty {
+		return nil
+	}
+
+	err := r.write()
+	if err != nil {
+		return err
+	}
+
+	r.dirty = false
+
+	return nil
+}
+
+// Run flushes the file every interval until ctx is done, calling onError when it fails.
+func (r *HARRecorder) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := r.Flush()
+			if err != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// write replaces the file in one go, so that readers never see a partial archive.
+func (r *HARRecorder) write() error {
+	data, err := json.MarshalIndent(&r.har, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal HAR: %w", err)
+	}
+
+	return writeFileAtomic(r.path, data)
+}
+
+// ReadHAR loads a HAR file.
+func ReadHAR(path string) (*HAR, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	har := &HAR{}
+
+	err = json.Unmarshal(data, har)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HAR file: %w", err)
+	}
+
+	// Base64 bodies are decoded up front, so that a bad one fails before anything is replayed
+	for idx, entry := range har.Log.Entries {
+		err := entry.Request.decodeBody()
+		if err != nil {
+			return nil, fmt.Errorf("entry %d of the HAR file: %w", idx+1, err)
+		}
+	}
+
+	return har, nil
+}
+
+// ReplayEntries returns the entries of a HAR file that should be sent again: the requests
+// received by amp listen if it wrote the file, or every entry of a file from another tool.
+func (h *HAR) ReplayEntries() []*HAREntry {
+	var received []*HAREntry
+
+	fromListener := false
+
+	for _, entry := range h.Log.Entries {
+		if entry.Role != "" {
+			fromListener = true
+		}
+
+		if entry.Role == HARReceived {
+			received = append(received, entry)
+		}
+	}
+
+	if !fromListener {
+		return h.Log.Entries
+	}
+
+	return received
+}
@@ -0,0 +1,110 @@
+package webhook
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHARRecorder(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "events.har")
+
+	recorder, err := NewHARRecorder(path, "test")
+	if err != nil {
+		t.Fatalf("NewHARRecorder() returned error: %v", err)
+	}
+
+	header := http.Header{"Content-Type": {"application/json"}}
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	// Forwards finish, and are recorded, before the response to the sender.
+	forwarded := NewHAREntry(start.Add(time.Millisecond), time.Millisecond,
+		NewHARRequest(http.MethodPost, "http://localhost:4000/webhook", header, []byte(`{"n":1}`)),
+		nil, errors.New("connection refused"))
+	forwarded.Role = HARForwarded
+
+	received := NewHAREntry(start, 2*time.Millisecond,
+		NewHARRequest(http.MethodPost, "http://127.0.0.1:1234/hook", header, []byte(`{"n":1}`)),
+		&Response{StatusCode: http.StatusOK, Header: header, Body: []byte(`ok`)}, nil)
+	received.Role = HARReceived
+
+	for _, entry := range []*HAREntry{forwarded, received} {
+		recorder.Add(entry)
+	}
+
+	// Entries are only written when the recorder is flushed.
+	har, err := ReadHAR(path)
+	if err != nil || len(har.Log.Entries) != 0 {
+		t.Fatalf("ReadHAR() before Flush() = %+v, %v, want an empty archive", har, err)
+	}
+
+	err = recorder.Flush()
+	if err != nil {
+		t.Fatalf("Flush() returned error: %v", err)
+	}
+
+	har, err = ReadHAR(path)
+	if err != nil {
+		t.Fatalf("ReadHAR() returned error: %v", err)
+	}
+
+	entries := har.Log.Entries
+	if len(entries) != 2 || entries[0].Role != HARReceived || entries[1].Role != HARForwarded {
+		t.Fatalf("entries are not in start order: %+v", entries)
+	}
+
+	if entries[1].Comment != "no response: connection refused" || entries[0].Response.Content.Text != "ok" {
+		t.Fatalf("unexpected entries: %+v, %+v", entries[0], entries[1])
+	}
+
+	replay := har.ReplayEntries()
+	if len(replay) != 1 || string(replay[0].Request.Body()) != `{"n":1}` ||
+		replay[0].Request.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("ReplayEntries() = %+v, want only the received request", replay)
+	}
+
+	// Bodies that aren't UTF-8 are written as base64 and decoded when read back.
+	binary := []byte{0xff, 0x00, 0xfe, 'a'}
+
+	recorder.Add(NewHAREntry(start.Add(time.Second), time.Millisecond,
+		NewHARRequest(http.MethodPost, "http://127.0.0.1:1234/hook", http.Header{}, binary),
+		&Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: binary}, nil))
+
+	err = recorder.Flush()
+	if err != nil {
+		t.Fatalf("Flush() returned error: %v", err)
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(raw), `"encoding": "base64"`) {
+		t.Fatalf("binary bodies were not written as base64:\n%s", raw)
+	}
+
+	har, err = ReadHAR(path)
+	if err != nil {
+		t.Fatalf("ReadHAR() returned error: %v", err)
+	}
+
+	if got := har.Log.Entries[2].Request.Body(); !bytes.Equal(got, binary) {
+		t.Fatalf("binary body read back as %v, want %v", got, binary)
+	}
+
+	// Files from other tools have no roles, all of their entries are replayed.
+	forwarded.Role, received.Role = "", ""
+
+	foreign := &HAR{Log: HARLog{Entries: []*HAREntry{received, forwarded}}}
+	if len(foreign.ReplayEntries()) != 2 {
+		t.Fatal("ReplayEntries() skipped entries of a HAR file without roles")
+	}
+}
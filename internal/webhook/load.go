@@ -0,0 +1,181 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidRate = errors.New("invalid rate, expected e.g. 50/s or 600/m")
+
+// MaxRate is the highest rate, in requests per second, that RunLoad can pace: a nanosecond
+// between requests.
+const MaxRate = float64(time.Second)
+
+// RenderPayload fills in the per-request placeholders of a payload template:
+// {{SEQ}} is the 1-based request number, {{UUID}} a random UUID and {{NOW}} the current time.
+func RenderPayload(template []byte, seq int) []byte {
+	payload := bytes.ReplaceAll(template, []byte("{{SEQ}}"), []byte(strconv.Itoa(seq)))
+	payload = bytes.ReplaceAll(payload, []byte("{{NOW}}"), []byte(time.Now().UTC().Format(time.RFC3339)))
+
+	for bytes.Contains(payload, []byte("{{UUID}}")) {
+		payload = bytes.Replace(payload, []byte("{{UUID}}"), []byte(uuid.NewString()), 1)
+	}
+
+	return payload
+}
+
+// ParseRate parses a request rate such as "50/s", "600/m" or "50", and returns it per second.
+func ParseRate(spec string) (float64, error) {
+	count, unit, _ := strings.Cut(strings.TrimSpace(spec), "/")
+
+	per := time.Second
+
+	switch unit {
+	case "", "s":
+	case "m":
+		per = time.Minute
+	case "h":
+		per = time.Hour
+	default:
+		return 0, fmt.Errorf("%w: unknown unit %q", ErrInvalidRate, unit)
+	}
+
+	value, err := strconv.ParseFloat(count, 64)
+	if err != nil || value <= 0 || math.IsInf(value, 0) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidRate, spec)
+	}
+
+	return value / per.Seconds(), nil
+}
+
+// LoadOptions control a load test. Rate is in requests per second, 0 means as fast as possible,
+// and must not be above MaxRate.
+type LoadOptions struct {
+	Count       int
+	Concurrency int
+	Rate        float64
+}
+
+// LoadReport summarizes the requests sent during a load test.
+type LoadReport struct {
+	Sent     int
+	Duration time.Duration
+	// Latencies of the requests that got a response, sorted.
+	Latencies []time.Duration
+	Statuses  map[int]int
+	Errors    map[string]int
+}
+
+// Percentile returns the latency below which the given fraction of responses fell.
+func (r *LoadReport) Percentile(fraction float64) time.Duration {
+	if len(r.Latencies) == 0 {
+		return 0
+	}
+
+	idx := int(math.Ceil(fraction*float64(len(r.Latencies)))) - 1
+
+	return r.Latencies[max(0, min(idx, len(r.Latencies)-1))]
+}
+
+// Throughput returns the number of requests sent per second.
+func (r *LoadReport) Throughput() float64 {
+	if r.Duration <= 0 {
+		return 0
+	}
+
+	return float64(r.Sent) / r.Duration.Seconds()
+}
+
+// RunLoad calls send opts.Count times, with at most opts.Concurrency calls in flight and
+// paced to opts.Rate, and collects the results. Send returns the response status code.
+// It stops early, without failing, if ctx is canceled.
+func RunLoad(ctx context.Context, opts LoadOptions, send func(ctx context.Context, seq int) (int, error)) *LoadReport {
+	report := &LoadReport{Statuses: map[int]int{}, Errors: map[string]int{}}
+	seqs := make(chan int)
+
+	go func() {
+		defer close(seqs)
+
+		var ticker *time.Ticker
+		if opts.Rate > 0 {
+			ticker = time.NewTicker(time.Duration(float64(time.Second) / opts.Rate))
+			defer ticker.Stop()
+		}
+
+		for seq := 1; seq <= opts.Count; seq++ {
+			// The first request goes out right away, the rest wait for the next tick
+			if ticker != nil && seq > 1 {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case seqs <- seq:
+			}
+		}
+	}()
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+
+	start := time.Now()
+
+	for range max(1, opts.Concurrency) {
+		wg.Go(func() {
+			for seq := range seqs {
+				sent := time.Now()
+				status, err := send(ctx, seq)
+				elapsed := time.Since(sent)
+
+				mu.Lock()
+				report.Sent++
+
+				if err != nil {
+					report.Errors[errorSummary(err)]++
+				} else {
+					report.Statuses[status]++
+					report.Latencies = append(report.Latencies, elapsed)
+				}
+				mu.Unlock()
+			}
+		})
+	}
+
+	wg.Wait()
+
+	report.Duration = time.Since(start)
+
+	sort.Slice(report.Latencies, func(i, j int) bool {
+		return report.Latencies[i] < report.Latencies[j]
+	})
+
+	return report
+}
+
+// errorSummary drops the method and URL from request errors, so that equal failures are counted together.
+func errorSummary(err error) string {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+
+	return err.Error()
+}
@@ -0,0 +1,106 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseRate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		spec    string
+		want    float64
+		wantErr bool
+	}{
+		{spec: "50", want: 50},
+		{spec: "50/s", want: 50},
+		{spec: "600/m", want: 10},
+		{spec: "7200/h", want: 2},
+		{spec: "0/s", wantErr: true},
+		{spec: "fast", wantErr: true},
+		{spec: "5/d", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseRate(tt.spec)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidRate) {
+				t.Errorf("ParseRate(%q) returned %v, want ErrInvalidRate", tt.spec, err)
+			}
+
+			continue
+		}
+
+		if err != nil || got != tt.want {
+			t.Errorf("ParseRate(%q) = %v, %v, want %v", tt.spec, got, err, tt.want)
+		}
+	}
+}
+
+func TestRenderPayload(t *testing.T) {
+	t.Parallel()
+
+	var got struct {
+		Seq int    `json:"seq"`
+		A   string `json:"a"`
+		B   string `json:"b"`
+	}
+
+	err := json.Unmarshal(RenderPayload([]byte(`{"seq":{{SEQ}},"a":"{{UUID}}","b":"{{UUID}}"}`), 7), &got)
+	if err != nil {
+		t.Fatalf("RenderPayload() returned invalid JSON: %v", err)
+	}
+
+	if got.Seq != 7 || len(got.A) != 36 || got.A == got.B {
+		t.Fatalf("RenderPayload() did not fill in the placeholders: %+v", got)
+	}
+}
+
+func TestRunLoad(t *testing.T) {
+	t.Parallel()
+
+	var inFlight, peak atomic.Int32
+
+	errBoom := errors.New("boom")
+
+	send := func(ctx context.Context, seq int) (int, error) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+
+		for {
+			old := peak.Load()
+			if current <= old || peak.CompareAndSwap(old, current) {
+				break
+			}
+		}
+
+		time.Sleep(time.Millisecond)
+
+		if seq%10 == 0 {
+			return 0, errBoom
+		}
+
+		return 200, nil
+	}
+
+	opts := LoadOptions{Count: 20, Concurrency: 3}
+
+	report := RunLoad(t.Context(), opts, send)
+
+	if report.Sent != 20 || report.Statuses[200] != 18 || report.Errors["boom"] != 2 {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+
+	if peak.Load() > 3 {
+		t.Fatalf("%d requests were in flight at once, want at most 3", peak.Load())
+	}
+
+	if len(report.Latencies) != 18 || report.Percentile(0.5) > report.Percentile(0.99) {
+		t.Fatalf("unexpected latencies: %v", report.Latencies)
+	}
+}
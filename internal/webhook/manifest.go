@@ -0,0 +1,282 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/amp-labs/cli/openapi"
+)
+
+// ManifestChecker compares the records in Ampersand read and subscribe deliveries with
+// the objects declared in a manifest, to catch fields that are missing or not mapped.
+type ManifestChecker struct {
+	manifest *openapi.Manifest
+}
+
+// NewManifestChecker returns a checker for deliveries of the integrations in manifest.
+func NewManifestChecker(manifest *openapi.Manifest) *ManifestChecker {
+	return &ManifestChecker{manifest: manifest}
+}
+
+// ampersandPayload is the part of an Ampersand webhook payload that the checker looks at.
+type ampersandPayload struct {
+	Action          string          `json:"action"`
+	Provider        string          `json:"provider"`
+	IntegrationName string          `json:"integrationName"`
+	ObjectName      string          `json:"objectName"`
+	Result          []payloadRecord `json:"result"`
+}
+
+type payloadRecord struct {
+	SubscribeEventType string         `json:"subscribeEventType"`
+	Fields             map[string]any `json:"fields"`
+	MappedFields       map[string]any `json:"mappedFields"`
+}
+
+// objectFields is what a manifest object declares about the fields of its records.
+type objectFields struct {
+	// required and optional hold the provider field names, lower-cased.
+	required map[string]bool
+	optional map[string]bool
+	// mapped holds the names that fields are mapped to, and whether the mapping is required.
+	mapped map[string]bool
+	// anyField is set when optionalFieldsAuto lets the user pick any field.
+	anyField bool
+}
+
+// Check returns a description of every way in which the webhook body doesn't match the
+// manifest. Bodies that aren't Ampersand deliveries are reported as such.
+func (c *ManifestChecker) Check(body []byte) []string {
+	var payload ampersandPayload
+
+	err := json.Unmarshal(body, &payload)
+	if err != nil || payload.ObjectName == "" {
+		return []string{"not an Ampersand delivery (no objectName), can't match it to the manifest"}
+	}
+
+	integration, object := c.findObject(&payload)
+	if integration == nil {
+		desc := fmt.Sprintf("object %q", payload.ObjectName)
+		if payload.Provider != "" {
+			desc += fmt.Sprintf(" of provider %q", payload.Provider)
+		}
+
+		return []string{desc + " is not a read or subscribe object of any integration in the manifest"}
+	}
+
+	var findings []string
+
+	if payload.Action == "subscribe" && findSubscribeObject(integration, object.name) == nil {
+		findings = append(findings, fmt.Sprintf("integration %q doesn't subscribe to object %q",
+			integration.Name, object.name))
+	}
+
+	if object.fields == nil {
+		return findings
+	}
+
+	return append(findings, checkRecords(object.fields, payload.Result)...)
+}
+
+// manifestObject is the manifest object that a delivery was matched to.
+type manifestObject struct {
+	name string
+	// fields is nil for subscribe objects that don't inherit the fields of a read object.
+	fields *objectFields
+}
+
+// findObject returns the object that a delivery is for, preferring integrations that match
+// the payload's integration name and provider. Subscribe deliveries are matched to subscribe
+// objects first, which use the read object's fields when inheritFieldsAndMapping is set.
+func (c *ManifestChecker) findObject(payload *ampersandPayload) (*openapi.Integration, *manifestObject) {
+	for idx := range c.manifest.Integrations {
+		integration := &c.manifest.Integrations[idx]
+
+		if payload.IntegrationName != "" && integration.Name != payload.IntegrationName {
+			continue
+		}
+
+		if payload.Provider != "" && !strings.EqualFold(integration.Provider, payload.Provider) {
+			continue
+		}
+
+		read := findReadObject(integration, payload.ObjectName)
+
+		subscribed := findSubscribeObject(integration, payload.ObjectName)
+		if subscribed != nil && (payload.Action == "subscribe" || read == nil) {
+			found := &manifestObject{name: subscribed.ObjectName}
+
+			if inherited := findReadObject(integration, subscribed.ObjectName); inherited != nil &&
+				subscribed.InheritFieldsAndMapping {
+				found.fields = declaredFields(inherited)
+			}
+
+			return integration, found
+		}
+
+		if read != nil {
+			return integration, &manifestObject{name: read.ObjectName, fields: declaredFields(read)}
+		}
+	}
+
+	return nil, nil
+}
+
+func findSubscribeObject(integration *openapi.Integration, objectName string) *openapi.IntegrationSubscribeObject {
+	if integration.Subscribe == nil || integration.Subscribe.Objects == nil {
+		return nil
+	}
+
+	for idx := range *integration.Subscribe.Objects {
+		object := &(*integration.Subscribe.Objects)[idx]
+		if strings.EqualFold(object.ObjectName, objectName) {
+			return object
+		}
+	}
+
+	return nil
+}
+
+func findReadObject(integration *openapi.Integration, objectName string) *openapi.IntegrationObject {
+	if integration.Read == nil || integration.Read.Objects == nil {
+		return nil
+	}
+
+	for idx := range *integration.Read.Objects {
+		object := &(*integration.Read.Objects)[idx]
+		if strings.EqualFold(object.ObjectName, objectName) ||
+			(object.MapToName != "" && object.MapToName == objectName) {
+			return object
+		}
+	}
+
+	return nil
+}
+
+func declaredFields(object *openapi.IntegrationObject) *objectFields {
+	fields := &objectFields{
+		required: map[string]bool{},
+		optional: map[string]bool{},
+		mapped:   map[string]bool{},
+		anyField: object.OptionalFieldsAuto != nil && *object.OptionalFieldsAuto == openapi.OptionalFieldsAutoOptionAll,
+	}
+
+	addFields := func(list *[]openapi.IntegrationField, names map[string]bool, required bool) {
+		if list == nil {
+			return
+		}
+
+		for _, field := range *list {
+			existent, err := field.AsIntegrationFieldExistent()
+			if err == nil && existent.FieldName != "" {
+				names[strings.ToLower(existent.FieldName)] = true
+
+				if existent.MapToName != "" {
+					fields.mapped[existent.MapToName] = required
+				}
+
+				continue
+			}
+
+			mapping, err := field.AsIntegrationFieldMapping()
+			if err == nil && mapping.MapToName != "" {
+				fields.mapped[mapping.MapToName] = required
+			}
+		}
+	}
+
+	addFields(object.RequiredFields, fields.required, true)
+	addFields(object.OptionalFields, fields.optional, false)
+
+	return fields
+}
+
+// checkRecords reports fields that are missing or unexpected, once per field for all records.
+func checkRecords(declared *objectFields, records []payloadRecord) []string {
+	missing := map[string]int{}
+	missingMapped := map[string]int{}
+	unexpected := map[string]bool{}
+	unmapped := map[string]bool{}
+	checked := 0
+
+	for _, record := range records {
+		for name := range record.Fields {
+			lower := strings.ToLower(name)
+			if !declared.anyField && !declared.required[lower] && !declared.optional[lower] {
+				unexpected[name] = true
+			}
+		}
+
+		for name := range record.MappedFields {
+			if _, ok := declared.mapped[name]; !ok {
+				unmapped[name] = true
+			}
+		}
+
+		// Deletions only carry the record's ID.
+		if record.SubscribeEventType == "delete" {
+			continue
+		}
+
+		checked++
+
+		for name := range declared.required {
+			if !hasField(record.Fields, name) {
+				missing[name]++
+			}
+		}
+
+		for name, required := range declared.mapped {
+			if _, ok := record.MappedFields[name]; required && !ok {
+				missingMapped[name]++
+			}
+		}
+	}
+
+	var findings []string
+
+	for _, name := range sortedKeys(missing) {
+		findings = append(findings, fmt.Sprintf("required field %q is missing in %d of %d record(s)",
+			name, missing[name], checked))
+	}
+
+	for _, name := range sortedKeys(missingMapped) {
+		findings = append(findings, fmt.Sprintf("required mapped field %q is missing in %d of %d record(s)",
+			name, missingMapped[name], checked))
+	}
+
+	if len(unexpected) > 0 {
+		findings = append(findings, "fields not declared in requiredFields or optionalFields: "+
+			strings.Join(sortedKeys(unexpected), ", "))
+	}
+
+	if len(unmapped) > 0 {
+		findings = append(findings, "mapped fields with no mapToName in the manifest: "+
+			strings.Join(sortedKeys(unmapped), ", "))
+	}
+
+	return findings
+}
+
+func hasField(fields map[string]any, lowerName string) bool {
+	for name := range fields {
+		if strings.ToLower(name) == lowerName {
+			return true
+		}
+	}
+
+	return false
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
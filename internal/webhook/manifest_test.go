@@ -0,0 +1,119 @@
+package webhook
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/amp-labs/cli/openapi"
+)
+
+func field(t *testing.T, name, mapTo string) openapi.IntegrationField {
+	t.Helper()
+
+	var fld openapi.IntegrationField
+
+	var err error
+	if name == "" {
+		err = fld.FromIntegrationFieldMapping(openapi.IntegrationFieldMapping{MapToName: mapTo})
+	} else {
+		err = fld.FromIntegrationFieldExistent(openapi.IntegrationFieldExistent{FieldName: name, MapToName: mapTo})
+	}
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return fld
+}
+
+func TestManifestChecker(t *testing.T) {
+	t.Parallel()
+
+	required := []openapi.IntegrationField{field(t, "email", ""), field(t, "", "priority")}
+	optional := []openapi.IntegrationField{field(t, "lastName", "surname")}
+	checker := NewManifestChecker(&openapi.Manifest{
+		Integrations: []openapi.Integration{{
+			Name:     "sf",
+			Provider: "salesforce",
+			Read: &openapi.IntegrationRead{Objects: &[]openapi.IntegrationObject{{
+				ObjectName:     "contact",
+				RequiredFields: &required,
+				OptionalFields: &optional,
+			}, {
+				ObjectName:     "account",
+				RequiredFields: &required,
+			}}},
+			Subscribe: &openapi.IntegrationSubscribe{Objects: &[]openapi.IntegrationSubscribeObject{
+				{ObjectName: "account", InheritFieldsAndMapping: true},
+				{ObjectName: "lead"},
+			}},
+		}},
+	})
+
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{
+			name: "matching read",
+			body: `{"action":"read","provider":"salesforce","objectName":"contact",
+				"result":[{"fields":{"Email":"a@b.c","lastname":"B"},"mappedFields":{"priority":1,"surname":"B"}}]}`,
+		},
+		{
+			name: "missing and unexpected fields",
+			body: `{"action":"read","provider":"salesforce","objectName":"contact",
+				"result":[{"fields":{"email":"a@b.c","phone":"1"},"mappedFields":{"priority":1,"rank":2}},
+				{"fields":{},"mappedFields":{}}]}`,
+			want: []string{
+				`required field "email" is missing in 1 of 2 record(s)`,
+				`required mapped field "priority" is missing in 1 of 2 record(s)`,
+				"fields not declared in requiredFields or optionalFields: phone",
+				"mapped fields with no mapToName in the manifest: rank",
+			},
+		},
+		{
+			name: "deleted records need no fields",
+			body: `{"action":"subscribe","provider":"salesforce","objectName":"contact",
+				"result":[{"subscribeEventType":"delete","fields":{}}]}`,
+			want: []string{`integration "sf" doesn't subscribe to object "contact"`},
+		},
+		{
+			name: "subscribe object inherits the read object's fields",
+			body: `{"action":"subscribe","provider":"salesforce","objectName":"account",
+				"result":[{"subscribeEventType":"update","fields":{"phone":"1"},"mappedFields":{"priority":1}}]}`,
+			want: []string{
+				`required field "email" is missing in 1 of 1 record(s)`,
+				"fields not declared in requiredFields or optionalFields: phone",
+			},
+		},
+		{
+			name: "subscribe-only object",
+			body: `{"action":"subscribe","provider":"salesforce","objectName":"lead",
+				"result":[{"subscribeEventType":"create","fields":{"email":"a@b.c"}}]}`,
+		},
+		{
+			name: "unknown object",
+			body: `{"action":"read","provider":"hubspot","objectName":"contact","result":[]}`,
+			want: []string{
+				`object "contact" of provider "hubspot" is not a read or subscribe object of any integration in the manifest`,
+			},
+		},
+		{
+			name: "not a delivery",
+			body: `{"hello":"world"}`,
+			want: []string{"not an Ampersand delivery (no objectName), can't match it to the manifest"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := checker.Check([]byte(tt.body))
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("Check() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
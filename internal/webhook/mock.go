@@ -0,0 +1,126 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrInvalidMockSequence = errors.New("invalid response sequence")
+
+// MaxMockRepeat caps the "xN" suffix, which expands to N entries of the sequence. Since the
+// last status repeats forever, longer runs are only needed before another status.
+const MaxMockRepeat = 10000
+
+// MockResponder answers webhooks itself instead of forwarding them, following a
+// scripted sequence of status codes. Once the sequence is exhausted, its last
+// status is repeated.
+type MockResponder struct {
+	Body  []byte
+	Delay time.Duration
+
+	mu       sync.Mutex
+	statuses []int
+	served   int
+}
+
+// MockResponse is the reply chosen for one request.
+type MockResponse struct {
+	StatusCode int
+	// Step is the 1-based position in the sequence, capped at the sequence length.
+	Step  int
+	Steps int
+}
+
+// ParseMockSequence parses a comma-separated list of status codes, where each
+// entry may be repeated with an "xN" suffix. For example "500x2,200" fails the
+// first two requests and accepts every request after that.
+func ParseMockSequence(spec string) ([]int, error) {
+	var statuses []int
+
+	for entry := range strings.SplitSeq(spec, ",") {
+		entry = strings.TrimSpace(entry)
+
+		code, count := entry, "1"
+		if before, after, ok := strings.Cut(entry, "x"); ok {
+			code, count = before, after
+		}
+
+		status, err := strconv.Atoi(code)
+		if err != nil || status < 100 || status > 599 {
+			return nil, fmt.Errorf("%w: %q is not an HTTP status code", ErrInvalidMockSequence, code)
+		}
+
+		repeat, err := strconv.Atoi(count)
+		if err != nil || repeat < 1 {
+			return nil, fmt.Errorf("%w: %q is not a valid repeat count", ErrInvalidMockSequence, count)
+		}
+
+		if repeat > MaxMockRepeat {
+			return nil, fmt.Errorf("%w: repeat count %d is above %d", ErrInvalidMockSequence, repeat, MaxMockRepeat)
+		}
+
+		for range repeat {
+			statuses = append(statuses, status)
+		}
+	}
+
+	if len(statuses) == 0 {
+		return nil, fmt.Errorf("%w: no status codes given", ErrInvalidMockSequence)
+	}
+
+	return statuses, nil
+}
+
+// NewMockResponder returns a responder that replies with the given statuses in order.
+func NewMockResponder(statuses []int, body []byte, delay time.Duration) *MockResponder {
+	return &MockResponder{
+		Body:     body,
+		Delay:    delay,
+		statuses: statuses,
+	}
+}
+
+// Next picks the response for the next request.
+func (m *MockResponder) Next() MockResponse {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	idx := min(m.served, len(m.statuses)-1)
+	m.served++
+
+	return MockResponse{
+		StatusCode: m.statuses[idx],
+		Step:       idx + 1,
+		Steps:      len(m.statuses),
+	}
+}
+
+// Write waits for the configured delay, then writes the response. It gives up
+// early if the sender goes away while waiting.
+func (m *MockResponder) Write(writer http.ResponseWriter, req *http.Request, resp MockResponse) {
+	if m.Delay > 0 {
+		select {
+		case <-req.Context().Done():
+			return
+		case <-time.After(m.Delay):
+		}
+	}
+
+	if len(m.Body) > 0 {
+		if json.Valid(m.Body) {
+			writer.Header().Set("Content-Type", "application/json")
+		} else {
+			writer.Header().Set("Content-Type", http.DetectContentType(m.Body))
+		}
+	}
+
+	writer.WriteHeader(resp.StatusCode)
+
+	_, _ = writer.Write(m.Body)
+}
@@ -0,0 +1,57 @@
+package webhook
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestParseMockSequence(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		spec    string
+		want    []int
+		wantErr bool
+	}{
+		{spec: "200", want: []int{200}},
+		{spec: "500x2,200", want: []int{500, 500, 200}},
+		{spec: " 503 , 429x2 ,204", want: []int{503, 429, 429, 204}},
+		{spec: "", wantErr: true},
+		{spec: "ok", wantErr: true},
+		{spec: "99", wantErr: true},
+		{spec: "500x0", wantErr: true},
+		{spec: "500x2000000000", wantErr: true},
+		{spec: "500,,200", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseMockSequence(tt.spec)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidMockSequence) {
+				t.Errorf("ParseMockSequence(%q) returned %v, want ErrInvalidMockSequence", tt.spec, err)
+			}
+
+			continue
+		}
+
+		if err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("ParseMockSequence(%q) = %v, %v, want %v", tt.spec, got, err, tt.want)
+		}
+	}
+}
+
+func TestMockResponderRepeatsLastStatus(t *testing.T) {
+	t.Parallel()
+
+	responder := NewMockResponder([]int{500, 200}, nil, 0)
+
+	got := make([]int, 0, 4)
+	for range 4 {
+		got = append(got, responder.Next().StatusCode)
+	}
+
+	if want := []int{500, 200, 200, 200}; !slices.Equal(got, want) {
+		t.Fatalf("Next() returned %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,294 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// DefaultSessionName is used by `amp listen` when no --name is given.
+const DefaultSessionName = "default"
+
+// lockFileExt is the extension of the files that serialize changes to shared files.
+const lockFileExt = ".lock"
+
+var (
+	ErrInvalidSessionName = errors.New("invalid session name, use letters, digits, '-' and '_'")
+	ErrSessionInUse       = errors.New("a listener with this session name is already running")
+	ErrSessionNotFound    = errors.New("no running listener found for session")
+	ErrNoSessions         = errors.New("no running listener found, start one with 'amp listen'")
+	ErrAmbiguousSession   = errors.New("several listeners are running, choose one with --session")
+
+	sessionNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+)
+
+// Session describes a running `amp listen` process.
+type Session struct {
+	Name      string    `json:"name"`
+	PID       int       `json:"pid"`
+	Address   string    `json:"address"`
+	ForwardTo []string  `json:"forwardTo,omitempty"`
+	StartedAt time.Time `json:"startedAt"`
+	// CAFile is set when the listener serves HTTPS, and holds the CA that signed its certificate.
+	CAFile string `json:"caFile,omitempty"`
+}
+
+// URL returns the URL that webhooks should be sent to for this session.
+func (s *Session) URL() string {
+	if s.CAFile != "" {
+		return "https://" + s.Address
+	}
+
+	return "http://" + s.Address
+}
+
+// Registry keeps track of running listeners, one file per session, so that several
+// listeners can run side by side and `amp trigger` can find the right one.
+type Registry struct {
+	dir string
+}
+
+// NewRegistry returns a registry that keeps its entries in dir.
+func NewRegistry(dir string) *Registry {
+	return &Registry{dir: dir}
+}
+
+// DefaultRegistry returns the registry in the user's cache directory.
+func DefaultRegistry() (*Registry, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewRegistry(filepath.Join(dir, "ampersand", "listeners")), nil
+}
+
+// ValidateSessionName checks that a session name can be used as a file name.
+func ValidateSessionName(name string) error {
+	if !sessionNamePattern.MatchString(name) {
+		return fmt.Errorf("%w: %q", ErrInvalidSessionName, name)
+	}
+
+	return nil
+}
+
+// Register records a running listener. It fails if another live listener already
+// uses the same name; a stale entry with the same name is replaced. The check and the
+// write happen under the lock of the name, so two listeners can't both take it.
+func (r *Registry) Register(ctx context.Context, session *Session) error {
+	err := ValidateSessionName(session.Name)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(r.dir, dirPerm)
+	if err != nil {
+		return err
+	}
+
+	unlock, err := r.lock(ctx, session.Name)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	existing, err := r.load(session.Name)
+	if err == nil && existing.PID != session.PID && isAlive(ctx, existing) {
+		return fmt.Errorf("%w: %q (pid %d, %s)", ErrSessionInUse, existing.Name, existing.PID, existing.Address)
+	}
+
+	data, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %w", err)
+	}
+
+	return os.WriteFile(r.path(session.Name), data, filePerm)
+}
+
+// Unregister removes the entry of a listener that is shutting down. Entries that
+// have since been taken over by another process are left alone.
+func (r *Registry) Unregister(session *Session) {
+	unlock, err := r.lock(context.Background(), session.Name)
+	if err != nil {
+		return
+	}
+	defer unlock()
+
+	existing, err := r.load(session.Name)
+	if err != nil || existing.PID != session.PID {
+		return
+	}
+
+	_ = os.Remove(r.path(session.Name))
+}
+
+// Live returns all running listeners sorted by name, removing entries of
+// listeners that are no longer running.
+func (r *Registry) Live(ctx context.Context) ([]*Session, error) {
+	entries, err := os.ReadDir(r.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var sessions []*Session
+
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".json")
+		if entry.IsDir() || !ok {
+			continue
+		}
+
+		session, err := r.load(name)
+		if err != nil || !isAlive(ctx, session) {
+			r.removeStale(ctx, name)
+
+			continue
+		}
+
+		sessions = append(sessions, session)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].Name < sessions[j].Name
+	})
+
+	return sessions, nil
+}
+
+// Find returns the running listener with the given name. If name is empty, it returns
+// the only running listener, or the default session if several are running.
+func (r *Registry) Find(ctx context.Context, name string) (*Session, error) {
+	sessions, err := r.Live(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if name == "" && len(sessions) == 1 {
+		return sessions[0], nil
+	}
+
+	lookup := name
+	if lookup == "" {
+		lookup = DefaultSessionName
+	}
+
+	names := make([]string, 0, len(sessions))
+
+	for _, session := range sessions {
+		if session.Name == lookup {
+			return session, nil
+		}
+
+		names = append(names, session.Name)
+	}
+
+	switch {
+	case len(sessions) == 0:
+		return nil, ErrNoSessions
+	case name == "":
+		return nil, fmt.Errorf("%w (running: %s)", ErrAmbiguousSession, strings.Join(names, ", "))
+	default:
+		return nil, fmt.Errorf("%w %q (running: %s)", ErrSessionNotFound, name, strings.Join(names, ", "))
+	}
+}
+
+// removeStale removes the entry of a listener that is no longer running, unless a new
+// listener has taken the name in the meantime.
+func (r *Registry) removeStale(ctx context.Context, name string) {
+	unlock, err := r.lock(ctx, name)
+	if err != nil {
+		return
+	}
+	defer unlock()
+
+	session, err := r.load(name)
+	if err == nil && isAlive(ctx, session) {
+		return
+	}
+
+	_ = os.Remove(r.path(name))
+}
+
+// lock takes the lock of a session name, so that only one process at a time changes its entry.
+func (r *Registry) lock(ctx context.Context, name string) (func(), error) {
+	unlock, err := lockFile(ctx, filepath.Join(r.dir, name+lockFileExt))
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock session %s: %w", name, err)
+	}
+
+	return unlock, nil
+}
+
+func (r *Registry) path(name string) string {
+	return filepath.Join(r.dir, name+".json")
+}
+
+func (r *Registry) load(name string) (*Session, error) {
+	data, err := os.ReadFile(r.path(name))
+	if err != nil {
+		return nil, err
+	}
+
+	session := &Session{}
+
+	err = json.Unmarshal(data, session)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse session %s: %w", name, err)
+	}
+
+	return session, nil
+}
+
+// isAlive checks that the listener's process still exists and that its port accepts connections.
+func isAlive(ctx context.Context, session *Session) bool {
+	if !processExists(session.PID) {
+		return false
+	}
+
+	const dialTimeout = 500 * time.Millisecond
+
+	dialer := net.Dialer{Timeout: dialTimeout}
+
+	conn, err := dialer.DialContext(ctx, "tcp", session.Address)
+	if err != nil {
+		return false
+	}
+
+	_ = conn.Close()
+
+	return true
+}
+
+func processExists(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+
+	// On Windows FindProcess already fails for missing processes, and signals aren't supported.
+	if runtime.GOOS == "windows" {
+		return true
+	}
+
+	err = proc.Signal(syscall.Signal(0))
+
+	return err == nil || errors.Is(err, os.ErrPermission)
+}
@@ -0,0 +1,153 @@
+package webhook
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	dir := t.TempDir()
+	registry := NewRegistry(dir)
+
+	_, err := registry.Find(ctx, "")
+	if !errors.Is(err, ErrNoSessions) {
+		t.Fatalf("Find() on empty registry returned %v, want ErrNoSessions", err)
+	}
+
+	var lc net.ListenConfig
+
+	listener, err := lc.Listen(ctx, "tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	defer listener.Close()
+
+	live := &Session{Name: "billing", PID: os.Getpid(), Address: listener.Addr().String(), StartedAt: time.Now()}
+
+	err = registry.Register(ctx, live)
+	if err != nil {
+		t.Fatalf("Register() returned error: %v", err)
+	}
+
+	err = registry.Register(ctx, &Session{Name: "billing", PID: os.Getpid() + 1, Address: live.Address})
+	if !errors.Is(err, ErrSessionInUse) {
+		t.Fatalf("Register() with a live duplicate name returned %v, want ErrSessionInUse", err)
+	}
+
+	err = registry.Register(ctx, &Session{Name: "../escape", PID: os.Getpid(), Address: live.Address})
+	if !errors.Is(err, ErrInvalidSessionName) {
+		t.Fatalf("Register() with a bad name returned %v, want ErrInvalidSessionName", err)
+	}
+
+	// A session whose port no longer accepts connections is stale.
+	closed, err := lc.Listen(ctx, "tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	stale := &Session{Name: "default", PID: os.Getpid(), Address: closed.Addr().String()}
+	_ = closed.Close()
+
+	err = registry.Register(ctx, stale)
+	if err != nil {
+		t.Fatalf("Register() returned error: %v", err)
+	}
+
+	session, err := registry.Find(ctx, "")
+	if err != nil {
+		t.Fatalf("Find() with one live session returned error: %v", err)
+	}
+
+	if session.Name != "billing" || session.URL() != "http://"+live.Address {
+		t.Fatalf("Find() returned unexpected session: %+v", session)
+	}
+
+	_, err = os.Stat(filepath.Join(dir, "default.json"))
+	if !os.IsNotExist(err) {
+		t.Fatalf("stale session entry was not removed: %v", err)
+	}
+
+	_, err = registry.Find(ctx, "orders")
+	if !errors.Is(err, ErrSessionNotFound) {
+		t.Fatalf("Find() with unknown name returned %v, want ErrSessionNotFound", err)
+	}
+
+	registry.Unregister(&Session{Name: "billing", PID: os.Getpid() + 1})
+
+	_, err = registry.Find(ctx, "billing")
+	if err != nil {
+		t.Fatalf("Unregister() by another process removed the session: %v", err)
+	}
+
+	registry.Unregister(live)
+
+	_, err = registry.Find(ctx, "billing")
+	if !errors.Is(err, ErrNoSessions) {
+		t.Fatalf("Find() after Unregister() returned %v, want ErrNoSessions", err)
+	}
+}
+
+func TestRegistryConcurrentRegister(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+
+	var lc net.ListenConfig
+
+	listener, err := lc.Listen(ctx, "tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+
+	defer listener.Close()
+
+	// Both processes are alive and accept connections, so only one may take the name.
+	pids := []int{os.Getpid(), os.Getppid()}
+
+	for range 10 {
+		registry := NewRegistry(t.TempDir())
+
+		var (
+			wg      sync.WaitGroup
+			mu      sync.Mutex
+			winners = map[int]bool{}
+		)
+
+		for idx := range 8 {
+			wg.Go(func() {
+				pid := pids[idx%len(pids)]
+
+				err := registry.Register(ctx, &Session{Name: "default", PID: pid, Address: listener.Addr().String()})
+				if err != nil && !errors.Is(err, ErrSessionInUse) {
+					t.Errorf("Register() returned %v", err)
+				}
+
+				if err == nil {
+					mu.Lock()
+					winners[pid] = true
+					mu.Unlock()
+				}
+			})
+		}
+
+		wg.Wait()
+
+		if len(winners) != 1 {
+			t.Fatalf("Register() let %d processes take the same name", len(winners))
+		}
+
+		_, err := os.Stat(filepath.Join(registry.dir, "default"+lockFileExt))
+		if !os.IsNotExist(err) {
+			t.Fatalf("the lock was not released: %v", err)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidTarget = errors.New("invalid forward target")
+	ErrInvalidRule   = errors.New("invalid routing rule")
+)
+
+// DefaultTimeout is how long a forward target gets to respond unless configured otherwise.
+const DefaultTimeout = 5 * time.Second
+
+// RuleKind identifies which part of an event a routing rule inspects.
+type RuleKind string
+
+const (
+	// PathRule matches the request path against a glob pattern, e.g. "path=/hubspot/*".
+	PathRule RuleKind = "path"
+	// HeaderRule matches a request header value, e.g. "header:X-Provider=hubspot".
+	HeaderRule RuleKind = "header"
+	// JSONRule matches a field of the JSON body by dotted path, e.g. "json:result.0.objectName=contact".
+	JSONRule RuleKind = "json"
+)
+
+// Rule is a single condition an event must satisfy to be forwarded to a target.
+type Rule struct {
+	Kind  RuleKind
+	Key   string
+	Value string
+}
+
+func (r Rule) String() string {
+	if r.Kind == PathRule {
+		return string(r.Kind) + "=" + r.Value
+	}
+
+	return string(r.Kind) + ":" + r.Key + "=" + r.Value
+}
+
+// Target is a forward destination, along with the rules that decide which events it receives.
+// A target without rules receives every event.
+type Target struct {
+	URL     string
+	Timeout time.Duration
+	Rules   []Rule
+	// Transport is used to reach the target, e.g. to trust a custom CA. If nil,
+	// http.DefaultTransport is used.
+	Transport http.RoundTripper
+}
+
+func (t *Target) String() string {
+	opts := make([]string, 0, len(t.Rules)+1)
+
+	for _, rule := range t.Rules {
+		opts = append(opts, rule.String())
+	}
+
+	opts = append(opts, "timeout "+t.Timeout.String())
+
+	return t.URL + " (" + strings.Join(opts, ", ") + ")"
+}
+
+// ParseTarget parses a forward target specification of the form
+//
+//	URL[;option...]
+//
+// where each option is either "timeout=<duration>" or a routing rule:
+// "path=<glob>", "header:<name>=<value>" or "json:<field.path>=<value>".
+// All rules of a target must match for an event to be forwarded to it.
+func ParseTarget(spec string, defaultTimeout time.Duration) (*Target, error) {
+	parts := strings.Split(spec, ";")
+
+	target := &Target{
+		URL:     strings.TrimSpace(parts[0]),
+		Timeout: defaultTimeout,
+	}
+
+	parsed, err := url.Parse(target.URL)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("%w: %q is not an absolute URL", ErrInvalidTarget, target.URL)
+	}
+
+	for _, opt := range parts[1:] {
+		opt = strings.TrimSpace(opt)
+		if opt == "" {
+			continue
+		}
+
+		if value, ok := strings.CutPrefix(opt, "timeout="); ok {
+			timeout, err := time.ParseDuration(value)
+			if err != nil || timeout <= 0 {
+				return nil, fmt.Errorf("%w: bad timeout %q for %s", ErrInvalidTarget, value, target.URL)
+			}
+
+			target.Timeout = timeout
+
+			continue
+		}
+
+		rule, err := parseRule(opt)
+		if err != nil {
+			return nil, err
+		}
+
+		target.Rules = append(target.Rules, rule)
+	}
+
+	return target, nil
+}
+
+func parseRule(opt string) (Rule, error) {
+	lhs, value, ok := strings.Cut(opt, "=")
+	if !ok {
+		return Rule{}, fmt.Errorf("%w: %q (expected <kind>=<value>)", ErrInvalidRule, opt)
+	}
+
+	if lhs == string(PathRule) {
+		_, err := path.Match(value, "/")
+		if err != nil {
+			return Rule{}, fmt.Errorf("%w: bad path pattern %q: %w", ErrInvalidRule, value, err)
+		}
+
+		return Rule{Kind: PathRule, Value: value}, nil
+	}
+
+	kind, key, ok := strings.Cut(lhs, ":")
+	if !ok || key == "" {
+		return Rule{}, fmt.Errorf("%w: %q (expected header:<name>=<value> or json:<field>=<value>)", ErrInvalidRule, opt)
+	}
+
+	switch RuleKind(kind) { //nolint:exhaustive
+	case HeaderRule, JSONRule:
+		return Rule{Kind: RuleKind(kind), Key: key, Value: value}, nil
+	default:
+		return Rule{}, fmt.Errorf("%w: unknown rule kind %q", ErrInvalidRule, kind)
+	}
+}
+
+// Route returns the targets that should receive the event, in the order they were given.
+func Route(targets []*Target, evt *Event) []*Target {
+	var (
+		payload any
+		parsed  bool
+	)
+
+	matched := make([]*Target, 0, len(targets))
+
+	for _, target := range targets {
+		ok := true
+
+		for _, rule := range target.Rules {
+			if rule.Kind == JSONRule && !parsed {
+				// Bodies that aren't JSON simply never match a JSON rule.
+				_ = json.Unmarshal(evt.Body, &payload)
+				parsed = true
+			}
+
+			if !rule.matches(evt, payload) {
+				ok = false
+
+				break
+			}
+		}
+
+		if ok {
+			matched = append(matched, target)
+		}
+	}
+
+	return matched
+}
+
+func (r Rule) matches(evt *Event, payload any) bool {
+	switch r.Kind {
+	case PathRule:
+		reqPath, _, _ := strings.Cut(evt.Path, "?")
+		ok, _ := path.Match(r.Value, reqPath)
+
+		return ok
+	case HeaderRule:
+		for _, value := range evt.Headers.Values(r.Key) {
+			if value == r.Value {
+				return true
+			}
+		}
+
+		return false
+	case JSONRule:
+		value, ok := lookupJSON(payload, strings.Split(r.Key, "."))
+
+		return ok && value == r.Value
+	default:
+		return false
+	}
+}
+
+// lookupJSON walks a decoded JSON document by object keys and array indexes,
+// returning the scalar found at the end as a string.
+func lookupJSON(doc any, keys []string) (string, bool) {
+	for _, key := range keys {
+		switch node := doc.(type) {
+		case map[string]any:
+			next, ok := node[key]
+			if !ok {
+				return "", false
+			}
+
+			doc = next
+		case []any:
+			idx, err := strconv.Atoi(key)
+			if err != nil || idx < 0 || idx >= len(node) {
+				return "", false
+			}
+
+			doc = node[idx]
+		default:
+			return "", false
+		}
+	}
+
+	switch value := doc.(type) {
+	case string:
+		return value, true
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(value), true
+	case nil:
+		return "null", true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,132 @@
+package webhook
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestParseTarget(t *testing.T) {
+	t.Parallel()
+
+	target, err := ParseTarget("http://localhost:5000/in; json:result.0.provider=hubspot ;path=/crm/*;"+
+		"header:X-Amp-Event=read;timeout=30s", DefaultTimeout)
+	if err != nil {
+		t.Fatalf("ParseTarget() returned error: %v", err)
+	}
+
+	if target.URL != "http://localhost:5000/in" {
+		t.Errorf("URL = %q", target.URL)
+	}
+
+	if target.Timeout != 30*time.Second {
+		t.Errorf("Timeout = %v, want 30s", target.Timeout)
+	}
+
+	want := []Rule{
+		{Kind: JSONRule, Key: "result.0.provider", Value: "hubspot"},
+		{Kind: PathRule, Value: "/crm/*"},
+		{Kind: HeaderRule, Key: "X-Amp-Event", Value: "read"},
+	}
+
+	if len(target.Rules) != len(want) {
+		t.Fatalf("Rules = %v, want %v", target.Rules, want)
+	}
+
+	for i := range want {
+		if target.Rules[i] != want[i] {
+			t.Errorf("Rules[%d] = %v, want %v", i, target.Rules[i], want[i])
+		}
+	}
+
+	for _, spec := range []string{
+		"localhost:4000",
+		"http://localhost:4000;timeout=soon",
+		"http://localhost:4000;provider=hubspot",
+		"http://localhost:4000;query:a=b",
+		"http://localhost:4000;path=[",
+	} {
+		_, err := ParseTarget(spec, DefaultTimeout)
+		if !errors.Is(err, ErrInvalidTarget) && !errors.Is(err, ErrInvalidRule) {
+			t.Errorf("ParseTarget(%q) returned %v, want an invalid target or rule error", spec, err)
+		}
+	}
+}
+
+func TestRoute(t *testing.T) {
+	t.Parallel()
+
+	mustParse := func(spec string) *Target {
+		target, err := ParseTarget(spec, DefaultTimeout)
+		if err != nil {
+			t.Fatalf("ParseTarget(%q) returned error: %v", spec, err)
+		}
+
+		return target
+	}
+
+	all := mustParse("http://localhost:4000")
+	hubspot := mustParse("http://localhost:5000;json:result.0.provider=hubspot")
+	crmPath := mustParse("http://localhost:6000;path=/crm/*")
+	header := mustParse("http://localhost:7000;header:x-amp-event=read;json:count=2")
+	targets := []*Target{all, hubspot, crmPath, header}
+
+	tests := []struct {
+		name string
+		evt  *Event
+		want []*Target
+	}{
+		{
+			name: "json field match",
+			evt: &Event{
+				Path: "/webhook",
+				Body: []byte(`{"result":[{"provider":"hubspot"}]}`),
+			},
+			want: []*Target{all, hubspot},
+		},
+		{
+			name: "path match ignores query",
+			evt: &Event{
+				Path: "/crm/contacts?x=1",
+				Body: []byte(`{"result":[{"provider":"salesforce"}]}`),
+			},
+			want: []*Target{all, crmPath},
+		},
+		{
+			name: "header and numeric json match",
+			evt: &Event{
+				Path:    "/webhook",
+				Headers: http.Header{"X-Amp-Event": []string{"read"}},
+				Body:    []byte(`{"count":2}`),
+			},
+			want: []*Target{all, header},
+		},
+		{
+			name: "non-JSON body only matches catch-all",
+			evt: &Event{
+				Path:    "/webhook",
+				Headers: http.Header{"X-Amp-Event": []string{"read"}},
+				Body:    []byte(`not json`),
+			},
+			want: []*Target{all},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := Route(targets, tt.evt)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Route() returned %d targets, want %d", len(got), len(tt.want))
+			}
+
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("Route()[%d] = %s, want %s", i, got[i].URL, tt.want[i].URL)
+				}
+			}
+		})
+	}
+}
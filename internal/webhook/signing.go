@@ -0,0 +1,55 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrInvalidSigningSecret = errors.New("invalid signing secret, expected whsec_ followed by base64")
+
+const signingSecretPrefix = "whsec_"
+
+// DeliveryHeaders returns the metadata headers that Ampersand's webhook deliveries carry:
+// a unique message ID, the send time and, if a signing secret is given, a signature of
+// the body that handlers can verify. They follow the Standard Webhooks conventions.
+func DeliveryHeaders(body []byte, secret string, now time.Time) (http.Header, error) {
+	msgId := "msg_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+
+	header := http.Header{}
+	header.Set("webhook-id", msgId)
+	header.Set("webhook-timestamp", strconv.FormatInt(now.Unix(), 10))
+
+	if secret != "" {
+		signature, err := Sign(secret, msgId, now, body)
+		if err != nil {
+			return nil, err
+		}
+
+		header.Set("webhook-signature", signature)
+	}
+
+	return header, nil
+}
+
+// Sign computes the webhook-signature header value for a message.
+func Sign(secret, msgId string, timestamp time.Time, body []byte) (string, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(secret, signingSecretPrefix))
+	if err != nil || len(key) == 0 {
+		return "", ErrInvalidSigningSecret
+	}
+
+	mac := hmac.New(sha256.New, key)
+	_, _ = fmt.Fprintf(mac, "%s.%d.", msgId, timestamp.Unix())
+	_, _ = mac.Write(body)
+
+	return "v1," + base64.StdEncoding.EncodeToString(mac.Sum(nil)), nil
+}
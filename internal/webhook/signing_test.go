@@ -0,0 +1,28 @@
+package webhook
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSign(t *testing.T) {
+	t.Parallel()
+
+	// Example from the Standard Webhooks specification.
+	got, err := Sign("whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw", "msg_p5jXN8AQM9LWM0D4loKWxJek",
+		time.Unix(1614265330, 0), []byte(`{"test": 2432232314}`))
+	if err != nil {
+		t.Fatalf("Sign() returned error: %v", err)
+	}
+
+	want := "v1,g0hM9SsE+OTPJTGt/tmIKtSyZlE3uFJELVlNIOLJ1OE="
+	if got != want {
+		t.Fatalf("Sign() = %s, want %s", got, want)
+	}
+
+	_, err = Sign("whsec_not base64!", "msg_1", time.Now(), nil)
+	if !errors.Is(err, ErrInvalidSigningSecret) {
+		t.Fatalf("Sign() with a bad secret returned %v, want ErrInvalidSigningSecret", err)
+	}
+}
@@ -0,0 +1,311 @@
+package webhook
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	ErrEventNotFound  = errors.New("event not found")
+	ErrAmbiguousEvent = errors.New("event ID prefix matches more than one event")
+)
+
+const (
+	dirPerm  = 0o700
+	filePerm = 0o600
+
+	eventFileExt = ".json"
+
+	// eventIdTime is the layout of the time in event IDs.
+	eventIdTime = "20060102T150405"
+)
+
+// The events kept by default, which is plenty to look back on a debugging session.
+const (
+	DefaultMaxEvents   = 1000
+	DefaultMaxEventAge = 7 * 24 * time.Hour
+)
+
+// Event is a single webhook delivery received by the local listener,
+// together with the outcome of forwarding it to the application.
+type Event struct {
+	Id         string           `json:"id"`
+	ReceivedAt time.Time        `json:"receivedAt"`
+	Method     string           `json:"method"`
+	Path       string           `json:"path"`
+	Headers    http.Header      `json:"headers"`
+	Body       []byte           `json:"body"`
+	Forwards   []*ForwardResult `json:"forwards,omitempty"`
+
+	// MockStatus is the status the listener answered with itself, when running with --respond.
+	MockStatus int `json:"mockStatus,omitempty"`
+
+	// ManifestFindings lists how the body differs from the manifest, when running with --manifest.
+	ManifestFindings []string `json:"manifestFindings,omitempty"`
+}
+
+// ForwardResult records what happened when an event was forwarded to one target.
+type ForwardResult struct {
+	URL        string        `json:"url"`
+	StatusCode int           `json:"statusCode,omitempty"`
+	Latency    time.Duration `json:"latency"`
+	Attempts   int           `json:"attempts,omitempty"`
+	Error      string        `json:"error,omitempty"`
+
+	// Redelivered is set when the event only reached the target from the dead-letter queue.
+	Redelivered bool `json:"redelivered,omitempty"`
+}
+
+// Delivered reports whether the target was reachable and accepted the event for processing.
+// Gateway and availability errors count as not delivered, since the app is likely restarting.
+func (r *ForwardResult) Delivered() bool {
+	return r.Error == "" && !isUnavailableStatus(r.StatusCode)
+}
+
+// NewEvent creates an event for a request that was just received.
+// IDs sort in the order events were received.
+func NewEvent(req *http.Request, body []byte) *Event {
+	now := time.Now().UTC()
+
+	return &Event{
+		Id:         newEventId(now),
+		ReceivedAt: now,
+		Method:     req.Method,
+		Path:       req.URL.RequestURI(),
+		Headers:    req.Header.Clone(),
+		Body:       body,
+	}
+}
+
+// Clone returns a deep copy of the event, which can be updated without affecting the original.
+func (e *Event) Clone() *Event {
+	cp := *e
+	cp.Headers = e.Headers.Clone()
+	cp.Body = slices.Clone(e.Body)
+	cp.ManifestFindings = slices.Clone(e.ManifestFindings)
+	cp.Forwards = make([]*ForwardResult, 0, len(e.Forwards))
+
+	for _, result := range e.Forwards {
+		resultCopy := *result
+		cp.Forwards = append(cp.Forwards, &resultCopy)
+	}
+
+	return &cp
+}
+
+// replaceForward records result in place of the failed forward to the same target.
+func (e *Event) replaceForward(result *ForwardResult) {
+	for idx, previous := range e.Forwards {
+		if previous.URL == result.URL && !previous.Delivered() {
+			e.Forwards[idx] = result
+
+			return
+		}
+	}
+}
+
+func newEventId(now time.Time) string {
+	const randomBytes = 3
+
+	suffix := make([]byte, randomBytes)
+	_, _ = rand.Read(suffix)
+
+	return "evt_" + now.Format(eventIdTime) + "_" + hex.EncodeToString(suffix)
+}
+
+// Store persists events as one JSON file per event in a directory.
+type Store struct {
+	dir string
+}
+
+// NewStore returns a store that keeps its events in dir.
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// DefaultStore returns the store in the user's cache directory, shared by
+// `amp listen` and `amp events`.
+func DefaultStore() (*Store, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewStore(filepath.Join(dir, "ampersand", "events")), nil
+}
+
+// Dir returns the directory the store writes to.
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Save writes the event to disk, replacing any earlier copy with the same ID.
+func (s *Store) Save(evt *Event) error {
+	err := os.MkdirAll(s.dir, dirPerm)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(evt)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	return os.WriteFile(filepath.Join(s.dir, evt.Id+eventFileExt), data, filePerm)
+}
+
+// List returns all stored events, oldest first.
+func (s *Store) List() ([]*Event, error) {
+	ids, err := s.ids()
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*Event, 0, len(ids))
+
+	for _, id := range ids {
+		evt, err := s.load(id)
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, evt)
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].ReceivedAt.Before(events[j].ReceivedAt)
+	})
+
+	return events, nil
+}
+
+// Get returns the event with the given ID. A unique prefix of an ID is also accepted.
+func (s *Store) Get(id string) (*Event, error) {
+	ids, err := s.ids()
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []string
+
+	for _, candidate := range ids {
+		if candidate == id {
+			return s.load(candidate)
+		}
+
+		if strings.HasPrefix(candidate, id) {
+			matches = append(matches, candidate)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("%w: %s", ErrEventNotFound, id)
+	case 1:
+		return s.load(matches[0])
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrAmbiguousEvent, id)
+	}
+}
+
+// Prune deletes the oldest events beyond the newest maxEvents, and the events received more
+// than maxAge ago. Zero disables either limit. It returns how many events were deleted.
+// Events are told apart by their IDs alone, so that pruning doesn't read every event.
+func (s *Store) Prune(maxEvents int, maxAge time.Duration) (int, error) {
+	ids, err := s.ids()
+	if err != nil {
+		return 0, err
+	}
+
+	// IDs sort in the order events were received, newest last
+	sort.Strings(ids)
+
+	var expired []string
+
+	if maxEvents > 0 && len(ids) > maxEvents {
+		expired = ids[:len(ids)-maxEvents]
+		ids = ids[len(ids)-maxEvents:]
+	}
+
+	if maxAge > 0 {
+		cutoff := time.Now().Add(-maxAge)
+
+		for _, id := range ids {
+			receivedAt, ok := eventIdReceivedAt(id)
+			if ok && receivedAt.Before(cutoff) {
+				expired = append(expired, id)
+			}
+		}
+	}
+
+	for idx, id := range expired {
+		err := os.Remove(filepath.Join(s.dir, id+eventFileExt))
+		if err != nil && !os.IsNotExist(err) {
+			return idx, err
+		}
+	}
+
+	return len(expired), nil
+}
+
+// eventIdReceivedAt returns the time in an event ID, to the second.
+func eventIdReceivedAt(id string) (time.Time, bool) {
+	parts := strings.Split(id, "_")
+	if len(parts) != 3 { //nolint:mnd
+		return time.Time{}, false
+	}
+
+	receivedAt, err := time.Parse(eventIdTime, parts[1])
+
+	return receivedAt, err == nil
+}
+
+func (s *Store) ids() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(entries))
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || filepath.Ext(name) != eventFileExt {
+			continue
+		}
+
+		ids = append(ids, strings.TrimSuffix(name, eventFileExt))
+	}
+
+	return ids, nil
+}
+
+func (s *Store) load(id string) (*Event, error) {
+	data, err := os.ReadFile(filepath.Join(s.dir, id+eventFileExt))
+	if err != nil {
+		return nil, err
+	}
+
+	evt := &Event{}
+
+	err = json.Unmarshal(data, evt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse event %s: %w", id, err)
+	}
+
+	return evt, nil
+}
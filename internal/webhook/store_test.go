@@ -0,0 +1,121 @@
+package webhook
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	store := NewStore(t.TempDir())
+
+	events, err := store.List()
+	if err != nil {
+		t.Fatalf("List() on empty store returned error: %v", err)
+	}
+
+	if len(events) != 0 {
+		t.Fatalf("List() on empty store returned %d events", len(events))
+	}
+
+	base := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	for idx, id := range []string{"evt_b_2", "evt_a_1", "evt_b_3"} {
+		err := store.Save(&Event{
+			Id:         id,
+			ReceivedAt: base.Add(time.Duration(idx) * time.Second),
+			Method:     "POST",
+			Body:       []byte(`{"n":1}`),
+		})
+		if err != nil {
+			t.Fatalf("Save(%s) returned error: %v", id, err)
+		}
+	}
+
+	events, err = store.List()
+	if err != nil {
+		t.Fatalf("List() returned error: %v", err)
+	}
+
+	got := make([]string, 0, len(events))
+	for _, evt := range events {
+		got = append(got, evt.Id)
+	}
+
+	want := []string{"evt_b_2", "evt_a_1", "evt_b_3"}
+	if len(got) != len(want) {
+		t.Fatalf("List() = %v, want %v", got, want)
+	}
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("List() = %v, want %v", got, want)
+		}
+	}
+
+	evt, err := store.Get("evt_a")
+	if err != nil {
+		t.Fatalf("Get() with unique prefix returned error: %v", err)
+	}
+
+	if evt.Id != "evt_a_1" || string(evt.Body) != `{"n":1}` {
+		t.Fatalf("Get() returned unexpected event: %+v", evt)
+	}
+
+	_, err = store.Get("evt_b")
+	if !errors.Is(err, ErrAmbiguousEvent) {
+		t.Fatalf("Get() with ambiguous prefix returned %v, want ErrAmbiguousEvent", err)
+	}
+
+	_, err = store.Get("evt_c")
+	if !errors.Is(err, ErrEventNotFound) {
+		t.Fatalf("Get() with unknown ID returned %v, want ErrEventNotFound", err)
+	}
+}
+
+func TestStorePrune(t *testing.T) {
+	t.Parallel()
+
+	store := NewStore(t.TempDir())
+	now := time.Now().UTC()
+
+	ids := []string{
+		newEventId(now.Add(-10 * 24 * time.Hour)),
+		newEventId(now.Add(-3 * time.Hour)),
+		newEventId(now.Add(-2 * time.Hour)),
+		newEventId(now.Add(-time.Hour)),
+	}
+
+	for _, id := range ids {
+		err := store.Save(&Event{Id: id})
+		if err != nil {
+			t.Fatalf("Save(%s) returned error: %v", id, err)
+		}
+	}
+
+	removed, err := store.Prune(0, 7*24*time.Hour)
+	if err != nil || removed != 1 {
+		t.Fatalf("Prune() by age = %d, %v, want 1 event removed", removed, err)
+	}
+
+	removed, err = store.Prune(2, 0)
+	if err != nil || removed != 1 {
+		t.Fatalf("Prune() by count = %d, %v, want 1 event removed", removed, err)
+	}
+
+	events, err := store.List()
+	if err != nil {
+		t.Fatalf("List() returned error: %v", err)
+	}
+
+	if len(events) != 2 || events[0].Id != ids[2] || events[1].Id != ids[3] {
+		t.Fatalf("List() after Prune() returned %d events, want the 2 newest", len(events))
+	}
+
+	removed, err = store.Prune(DefaultMaxEvents, DefaultMaxEventAge)
+	if err != nil || removed != 0 {
+		t.Fatalf("Prune() within the limits = %d, %v, want nothing removed", removed, err)
+	}
+}
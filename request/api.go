@@ -327,6 +327,29 @@ func (c *APIClient) PatchDestination(ctx context.Context, id string, patch *Patc
 	return &out, nil
 }
 
+// GetObjectMetadata returns the fields of a provider object, as seen by the connection of the group.
+func (c *APIClient) GetObjectMetadata(ctx context.Context,
+	provider, objectName, groupRef string,
+) (*ObjectMetadata, error) {
+	query := url.Values{"groupRef": {groupRef}}
+	getURL := fmt.Sprintf("%s/projects/%s/providers/%s/objects/%s/metadata?%s", c.Root, c.ProjectId,
+		url.PathEscape(provider), url.PathEscape(objectName), query.Encode())
+
+	auth, err := c.getAuthHeader(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var out ObjectMetadata
+
+	_, err = c.Client.Get(ctx, getURL, &out, auth) //nolint:bodyclose
+	if err != nil {
+		return nil, err
+	}
+
+	return &out, nil
+}
+
 func (c *APIClient) DeleteInstallation(ctx context.Context, integrationId string, installationId string) error {
 	delURL := fmt.Sprintf(
 		"%s/projects/%s/integrations/%s/installations/%s", c.Root, c.ProjectId, integrationId, installationId,
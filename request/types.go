@@ -31,6 +31,7 @@ type Group struct {
 type Connection struct {
 	Id                   string             `json:"id"`
 	ProjectId            string             `json:"projectId"`
+	Provider             string             `json:"provider"`
 	ProviderApp          *ProviderApp       `json:"providerApp,omitempty"`
 	Group                *Group             `json:"group"`
 	Consumer             *Consumer          `json:"consumer"`
@@ -119,3 +120,10 @@ type WebhookMetadata struct {
 	SvixAppId      string            `json:"svixAppId,omitempty"`
 	SvixEndpointId string            `json:"svixEndpointId,omitempty"`
 }
+
+// ObjectMetadata describes an object of a provider and its fields, as seen through a connection.
+type ObjectMetadata struct {
+	Name        string                           `json:"name"`
+	DisplayName string                           `json:"displayName"`
+	Fields      map[string]openapi.FieldMetadata `json:"fields"`
+}